@@ -6,6 +6,7 @@ import (
 	"deskapp/src/internal/config" // ⬅ NOVO IMPORT
 	"deskapp/src/internal/database"
 	"deskapp/src/internal/utils"
+	"fmt"
 )
 
 type BaseApp struct {
@@ -52,4 +53,24 @@ func (ba *BaseApp) GetConfig() *config.Config {
 
 func (ba *BaseApp) GetDB() *sql.DB {
 	return database.GetDB()
-}
\ No newline at end of file
+}
+
+// GetReplicaDB retorna a réplica de leitura (ou o primary, se não houver réplica).
+func (ba *BaseApp) GetReplicaDB() *sql.DB {
+	return database.GetReplica()
+}
+
+// GetNamedDB retorna uma conexão configurada em DATABASE_URL_<NOME>.
+func (ba *BaseApp) GetNamedDB(name string) (*sql.DB, error) {
+	db, ok := database.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("conexão %q não configurada", name)
+	}
+	return db, nil
+}
+
+// GetDBHealth retorna o estado da conexão com o banco, para as páginas
+// mostrarem "banco offline" em vez de falhar.
+func (ba *BaseApp) GetDBHealth() database.HealthStatus {
+	return database.Health()
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"database/sql"
 	"deskapp/src/internal/config"
+	"deskapp/src/internal/database"
 	"deskapp/src/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -16,4 +17,7 @@ type AppInterface interface {
 	GetConfig() *config.Config
 	GetLogger() *utils.Logger
     GetDB() *sql.DB
+    GetReplicaDB() *sql.DB
+    GetNamedDB(name string) (*sql.DB, error)
+    GetDBHealth() database.HealthStatus
 }
\ No newline at end of file
@@ -2,6 +2,7 @@
 package app
 
 import (
+	"deskapp/src/apps/core/model/repository"
 	"deskapp/src/internal/config"
 	"deskapp/src/internal/utils"
 	functemplates "deskapp/src/internal/utils/func_templates"
@@ -28,6 +29,8 @@ type AppManager struct {
 	cfg        *config.Config
 	staticFS   fs.FS
 	templateFS fs.FS
+	queries    *repository.QueryRecorder // consultas por requisição (modo DEBUG)
+	migrations fs.FS                     // aplicadas antes das rotas (DB_AUTO_MIGRATE)
 }
 
 func NewAppManager(logger *utils.Logger, cfg *config.Config, staticFS fs.FS, templateFS fs.FS) *AppManager {
@@ -53,6 +56,8 @@ func NewAppManager(logger *utils.Logger, cfg *config.Config, staticFS fs.FS, tem
 	am.setupMultiTemplates()
 	// Depois configure arquivos estáticos
 	am.SetupStatic()
+	// Log de SQL e, no modo DEBUG, a página /debug/queries
+	am.setupQueryLog()
 
 	return am
 }
@@ -251,6 +256,11 @@ func (am *AppManager) SetupStatic() {
 }
 
 func (am *AppManager) RegisterAllRoutes() {
+	// Com DB_AUTO_MIGRATE, não sobe com o schema desatualizado
+	if err := am.applyMigrations(); err != nil {
+		am.logger.Fatalf("❌ Falha ao aplicar migrações: %v", err)
+	}
+
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 	am.router.SetTrustedProxies([]string{"localhost"})
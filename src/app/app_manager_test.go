@@ -4,6 +4,7 @@ package app
 import (
 	"database/sql"
 	"deskapp/src/internal/config"
+	"deskapp/src/internal/database"
 	"deskapp/src/internal/utils"
 	"errors"
 	"fmt"
@@ -28,6 +29,9 @@ type MockApp struct {
 func (m *MockApp) GetConfig() *config.Config   { panic("unimplemented") }
 func (m *MockApp) GetControllers() []interface{} { return nil }
 func (m *MockApp) GetDB() *sql.DB                { panic("unimplemented") }
+func (m *MockApp) GetReplicaDB() *sql.DB         { panic("unimplemented") }
+func (m *MockApp) GetNamedDB(name string) (*sql.DB, error) { panic("unimplemented") }
+func (m *MockApp) GetDBHealth() database.HealthStatus { panic("unimplemented") }
 func (m *MockApp) GetLogger() *utils.Logger      { panic("unimplemented") }
 func (m *MockApp) GetName() string               { return m.name }
 func (m *MockApp) GetVersion() string            { return m.version }
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"deskapp/src/internal/database"
+	"deskapp/src/internal/migrations"
+	"fmt"
+	"io/fs"
+)
+
+// SetMigrations define de onde vêm as migrações aplicadas na inicialização
+// quando DB_AUTO_MIGRATE=true (normalmente o MigrationsFS embutido).
+func (am *AppManager) SetMigrations(source fs.FS) {
+	am.migrations = source
+}
+
+// applyMigrations aplica as migrações pendentes antes das rotas serem
+// registradas. Com a opção ligada, banco offline, estado dirty ou falha
+// na migração impedem a aplicação de iniciar com o schema desatualizado.
+func (am *AppManager) applyMigrations() error {
+	if !am.cfg.AutoMigrate {
+		return nil
+	}
+	if am.migrations == nil {
+		return fmt.Errorf("DB_AUTO_MIGRATE ligado, mas nenhuma migração foi definida (SetMigrations)")
+	}
+
+	db := database.GetDB()
+	if db == nil || !database.Health().Online() {
+		return fmt.Errorf("banco indisponível para aplicar as migrações: %w", database.ErrOffline)
+	}
+
+	mm, err := migrations.NewMigrationManager(db, am.migrations, am.cfg.DBDSN)
+	if err != nil {
+		return err
+	}
+	defer mm.Close()
+
+	am.logger.Info("Aplicando migrações pendentes...")
+	return mm.Up()
+}
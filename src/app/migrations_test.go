@@ -0,0 +1,45 @@
+package app
+
+import (
+	"deskapp/src/internal/config"
+	"deskapp/src/internal/database"
+	"deskapp/src/internal/utils"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyMigrations aplica migrações de um fs.FS em memória num SQLite
+// e recusa seguir com o banco dirty.
+func TestApplyMigrations(t *testing.T) {
+	const dsn = "sqlite://:memory:"
+	t.Cleanup(database.CloseAll)
+	db, err := database.InitDB(dsn)
+	require.NoError(t, err)
+
+	cfg := &config.Config{DBDSN: dsn}
+	am := &AppManager{logger: utils.NewLogger(), cfg: cfg}
+	am.SetMigrations(fstest.MapFS{
+		"20250101000000_notas.up.sql":   {Data: []byte(`CREATE TABLE notas (id INTEGER PRIMARY KEY, texto TEXT);`)},
+		"20250101000000_notas.down.sql": {Data: []byte(`DROP TABLE notas;`)},
+	})
+
+	// Sem DB_AUTO_MIGRATE nada é aplicado
+	require.NoError(t, am.applyMigrations())
+	var exists bool
+	require.NoError(t, db.QueryRow(`SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE name = 'notas')`).Scan(&exists))
+	assert.False(t, exists)
+
+	cfg.AutoMigrate = true
+	require.NoError(t, am.applyMigrations())
+	require.NoError(t, db.QueryRow(`SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE name = 'notas')`).Scan(&exists))
+	assert.True(t, exists)
+	// Rodar de novo não faz nada
+	require.NoError(t, am.applyMigrations())
+
+	_, err = db.Exec(`UPDATE schema_migrations SET dirty = 1`)
+	require.NoError(t, err)
+	assert.ErrorContains(t, am.applyMigrations(), "dirty")
+}
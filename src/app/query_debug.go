@@ -0,0 +1,60 @@
+package app
+
+import (
+	"deskapp/src/apps/core/model/repository"
+	"deskapp/src/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	debugQueriesPath   = "/debug/queries"
+	queriesPerRequest  = 50 // últimos comandos guardados de cada requisição
+	recentRequestsKept = 30 // requisições mostradas na página de depuração
+)
+
+// setupQueryLog liga o log de SQL dos repositórios: consultas lentas viram
+// WARNING sempre; no modo DEBUG todos os comandos vão para o log e os
+// últimos de cada requisição aparecem em /debug/queries.
+func (am *AppManager) setupQueryLog() {
+	debug := am.cfg.GetMode() == utils.DEBUG
+	repository.SetSlowQueryThreshold(am.cfg.SlowQuery)
+
+	var observer repository.QueryObserver = repository.NewLogObserver(am.logger, debug)
+	if debug {
+		am.queries = repository.NewQueryRecorder(queriesPerRequest, recentRequestsKept)
+		observer = repository.MultiObserver(observer, am.queries)
+
+		// Controllers costumam passar o *gin.Context como ctx; o fallback
+		// faz o Value dele enxergar o contexto da requisição
+		am.router.ContextWithFallback = true
+		am.router.Use(am.recordQueries)
+		am.router.GET(debugQueriesPath, am.debugQueries)
+		am.logger.Infof("🔎 Consultas por requisição disponíveis em %s", debugQueriesPath)
+	}
+	repository.SetQueryObserver(observer)
+}
+
+// recordQueries anexa um registro de comandos ao contexto da requisição.
+func (am *AppManager) recordQueries(c *gin.Context) {
+	path := c.Request.URL.Path
+	if strings.HasPrefix(path, "/static/") || path == debugQueriesPath {
+		c.Next()
+		return
+	}
+
+	ctx, req := am.queries.Begin(c.Request.Context(), c.Request.Method+" "+path)
+	c.Request = c.Request.WithContext(ctx)
+	defer am.queries.End(req)
+	c.Next()
+}
+
+// debugQueries mostra os comandos das últimas requisições.
+func (am *AppManager) debugQueries(c *gin.Context) {
+	c.HTML(http.StatusOK, "debug_queries", gin.H{
+		"Requests":  am.queries.Recent(),
+		"SlowQuery": am.cfg.SlowQuery,
+	})
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"deskapp/src/apps/core/model/repository"
+	"deskapp/src/internal/database"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorStatus traduz os erros do repositório para o status HTTP adequado.
+// Erros desconhecidos viram 500.
+func ErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, database.ErrOffline):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrUniqueViolation),
+		errors.Is(err, repository.ErrForeignKeyViolation),
+		errors.Is(err, repository.ErrStale):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrCheckViolation):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// RespondError responde com o status de ErrorStatus e um JSON {"error": ...}.
+// Em violações de constraint a coluna e a constraint também são enviadas.
+// Erros 500 são registrados no log e o detalhe não é exposto ao cliente;
+// com o banco offline (ver database.Health) eles viram 503.
+func (bc *BaseController) RespondError(ctx *gin.Context, err error) {
+	status := ErrorStatus(err)
+	if status == http.StatusInternalServerError && !bc.app.GetDBHealth().Online() {
+		status = http.StatusServiceUnavailable
+	}
+	if status == http.StatusServiceUnavailable {
+		ctx.AbortWithStatusJSON(status, gin.H{"error": database.ErrOffline.Error()})
+		return
+	}
+	if status == http.StatusInternalServerError {
+		bc.LogError("%s %s: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+		ctx.AbortWithStatusJSON(status, gin.H{"error": http.StatusText(status)})
+		return
+	}
+
+	// A mensagem do sentinela é mais útil ao cliente que o texto com o contexto interno
+	body := gin.H{"error": err.Error()}
+	for _, sentinel := range []error{repository.ErrNotFound, repository.ErrStale} {
+		if errors.Is(err, sentinel) {
+			body["error"] = sentinel.Error()
+		}
+	}
+	var cErr *repository.ConstraintError
+	if errors.As(err, &cErr) {
+		body["error"] = cErr.Kind.Error()
+		body["constraint"] = cErr.Constraint
+		if cErr.Column != "" {
+			body["column"] = cErr.Column
+		}
+	}
+	ctx.AbortWithStatusJSON(status, body)
+}
+
+// RequireDB é um middleware que responde 503 enquanto o banco estiver
+// offline, evitando que o handler chegue a consultar um pool sem conexão:
+//
+//	router.GET("/usuarios", ctl.RequireDB(), ctl.List)
+func (bc *BaseController) RequireDB() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !bc.app.GetDBHealth().Online() {
+			bc.RespondError(ctx, database.ErrOffline)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -3,6 +3,8 @@ package controller
 import (
 	"deskapp/src/app"
 	"deskapp/src/internal/utils"
+
+	"github.com/gin-gonic/gin"
 )
 
 // IController define a interface que todos os controllers devem implementar
@@ -14,5 +16,7 @@ type IController interface {
     LogInfo(format string, args ...interface{})
     LogError(format string, args ...interface{})
     LogWarning(format string, args ...interface{})
+    RespondError(ctx *gin.Context, err error)
+    RequireDB() gin.HandlerFunc
     
 }
\ No newline at end of file
@@ -1,5 +1,6 @@
 package entities
 
+import "context"
 
 type DBScanner interface {
 	Scan(dest ...any) error
@@ -8,4 +9,87 @@ type DBScanner interface {
 type Entity interface {
 	Columns() []string
 	ScanRow(row DBScanner) error
-}
\ No newline at end of file
+}
+
+// ColumnValuer é opcional: devolve os valores das colunas na ordem de Columns(),
+// sem reflexão (gerado pelo script gen-entity). O repositório o prefere à
+// leitura dos campos por tag.
+type ColumnValuer interface {
+	Values() []any
+}
+
+// PrimaryKeyer é opcional: entidades que a implementam declaram sua(s) chave(s)
+// primária(s). Sem ela o repositório procura a tag `pk:"true"` e, por fim, a coluna "id".
+type PrimaryKeyer interface {
+	PrimaryKey() []string
+}
+
+// Versioned é opcional: entidades que a implementam indicam a coluna usada no
+// lock otimista. Sem ela o repositório usa a coluna "version", se existir.
+type Versioned interface {
+	VersionColumn() string
+}
+
+// RelationKind indica a cardinalidade de um relacionamento.
+type RelationKind uint8
+
+const (
+	BelongsTo RelationKind = iota // esta entidade guarda a FK (ex: pedido.usuario_id -> usuario.id)
+	HasOne                        // a outra tabela guarda a FK, no máximo um registro
+	HasMany                       // a outra tabela guarda a FK, vários registros
+)
+
+// Relation descreve um relacionamento usado pelo Preload do QueryBuilder.
+// Name é também o nome do campo da struct que recebe os registros:
+// *Outra para BelongsTo/HasOne e []*Outra para HasMany.
+type Relation struct {
+	Name       string
+	Kind       RelationKind
+	Table      string
+	Schema     string
+	LocalKey   string // coluna desta entidade
+	ForeignKey string // coluna da tabela relacionada
+}
+
+// Relational é opcional: entidades que a implementam podem usar Preload.
+type Relational interface {
+	Relations() []Relation
+}
+
+/*
+Hooks opcionais do ciclo de vida. O repositório chama o método quando a
+entidade o implementa, com o mesmo ctx da operação (dentro de WithTx é a
+própria transação, então consultas feitas no hook participam dela).
+Um erro em um hook Before* cancela a operação; em After* é devolvido ao
+chamador (dentro de WithTx, desfazendo a transação).
+*/
+
+// BeforeInserter é chamado antes do INSERT (Insert, InsertMany, CopyMany e Upsert).
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInserter é chamado depois do INSERT, com a entidade já recarregada.
+type AfterInserter interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// BeforeUpdater é chamado antes do UPDATE de Update.
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdater é chamado depois do UPDATE de Update.
+type AfterUpdater interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// BeforeDeleter é chamado antes de Delete e ForceDelete.
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterFinder é chamado para cada entidade lida pelo QueryBuilder.
+type AfterFinder interface {
+	AfterFind(ctx context.Context) error
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"deskapp/src/apps/core/model/entities"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Count retorna o total de linhas que satisfazem as condições.
+// ORDER BY, LIMIT e OFFSET são ignorados; com GROUP BY conta os grupos.
+func (qb *QueryBuilder[T, P]) Count() (int64, error) {
+	if qb.err != nil {
+		return 0, qb.err
+	}
+	var query string
+	var args []any
+	if len(qb.groupBy) > 0 {
+		inner, innerArgs := qb.buildSQL("1", false)
+		query, args = fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS sub", inner), innerArgs
+	} else {
+		query, args = qb.buildSQL("COUNT(*)", false)
+	}
+
+	var total int64
+	if err := qb.repo.queryRow(qb.ctx, qb.repo.reader(qb.ctx), query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("erro ao contar registros: %w", err)
+	}
+	return total, nil
+}
+
+// Exists informa se ao menos uma linha satisfaz as condições.
+func (qb *QueryBuilder[T, P]) Exists() (bool, error) {
+	if qb.err != nil {
+		return false, qb.err
+	}
+	inner, args := qb.buildSQL("1", false)
+	query := fmt.Sprintf("SELECT EXISTS (%s)", inner)
+
+	var exists bool
+	if err := qb.repo.queryRow(qb.ctx, qb.repo.reader(qb.ctx), query, args...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("erro ao verificar existência: %w", err)
+	}
+	return exists, nil
+}
+
+// Sum retorna SUM(col); sem linhas o resultado é 0.
+func (qb *QueryBuilder[T, P]) Sum(col string) (float64, error) {
+	var total float64
+	err := qb.scalar("COALESCE(SUM(%s), 0)", col, &total)
+	return total, err
+}
+
+// Avg retorna AVG(col); sem linhas o resultado é 0.
+func (qb *QueryBuilder[T, P]) Avg(col string) (float64, error) {
+	var avg float64
+	err := qb.scalar("COALESCE(AVG(%s), 0)", col, &avg)
+	return avg, err
+}
+
+// Min escaneia MIN(col) em dest. Use um tipo anulável (ex: sql.NullTime)
+// se a consulta puder não retornar linhas.
+func (qb *QueryBuilder[T, P]) Min(col string, dest any) error {
+	return qb.scalar("MIN(%s)", col, dest)
+}
+
+// Max escaneia MAX(col) em dest. Use um tipo anulável (ex: sql.NullInt64)
+// se a consulta puder não retornar linhas.
+func (qb *QueryBuilder[T, P]) Max(col string, dest any) error {
+	return qb.scalar("MAX(%s)", col, dest)
+}
+
+// scalar executa um SELECT de uma única agregação (format) sobre col. Como
+// na ordenação, col precisa ser uma das colunas da entidade: nunca uma
+// expressão vinda do usuário.
+func (qb *QueryBuilder[T, P]) scalar(format, col string, dest any) error {
+	if qb.err != nil {
+		return qb.err
+	}
+	if !qb.isColumn(col) {
+		return fmt.Errorf("coluna inválida na agregação: %q", col)
+	}
+	expr := fmt.Sprintf(format, col)
+	query, args := qb.buildSQL(expr, false)
+	if err := qb.repo.queryRow(qb.ctx, qb.repo.reader(qb.ctx), query, args...).Scan(dest); err != nil {
+		return fmt.Errorf("erro ao calcular %s: %w", expr, err)
+	}
+	return nil
+}
+
+// PluckInto lê uma única coluna da entidade de todas as linhas para dest,
+// que deve ser um ponteiro para slice (ex: *[]string).
+func (qb *QueryBuilder[T, P]) PluckInto(col string, dest any) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("pluck: destino deve ser ponteiro para slice, recebido %T", dest)
+	}
+	slice = slice.Elem()
+	if qb.err != nil {
+		return qb.err
+	}
+	if !qb.isColumn(col) {
+		return fmt.Errorf("pluck: coluna inválida: %q", col)
+	}
+
+	query, args := qb.buildSQL(col, true)
+	rows, err := qb.repo.query(qb.ctx, qb.repo.reader(qb.ctx), query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := reflect.New(slice.Type().Elem())
+		if err := rows.Scan(item.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+	return rows.Err()
+}
+
+// Pluck é a versão tipada de PluckInto.
+// Ex: nomes, err := repository.Pluck[string](repo.Where(ctx, "age > ?", 18), "name")
+func Pluck[V any, T any, P interface { *T; entities.Entity }](qb IQueryBuilder[T, P], col string) ([]V, error) {
+	var values []V
+	if err := qb.PluckInto(col, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+/*
+ScanInto executa um SELECT com as expressões informadas (ex: "status",
+"COUNT(*) AS total") respeitando WHERE, GROUP BY, HAVING, ORDER BY e LIMIT,
+e escaneia o resultado em dest:
+
+  - *[]Struct ou *[]*Struct: colunas casadas pela tag `json` (ou nome do campo em minúsculas)
+  - *[]map[string]any: uma entrada por coluna
+*/
+func (qb *QueryBuilder[T, P]) ScanInto(dest any, exprs ...string) error {
+	if len(exprs) == 0 {
+		return fmt.Errorf("scan: nenhuma expressão de SELECT informada")
+	}
+	if qb.err != nil {
+		return qb.err
+	}
+
+	query, args := qb.buildSQL(strings.Join(exprs, ", "), true)
+	rows, err := qb.repo.query(qb.ctx, qb.repo.reader(qb.ctx), query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanRowsInto(rows, dest)
+}
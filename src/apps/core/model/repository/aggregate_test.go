@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestQueryBuilder_Count(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	// ORDER BY e LIMIT não entram na contagem
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "public"."users" WHERE age > $1`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	total, err := repo.Where(ctx, "age > ?", 18).OrderBy("name").Limit(10).Count()
+	if err != nil {
+		t.Errorf("Count falhou: %s", err)
+	}
+	if total != 42 {
+		t.Errorf("Esperava 42, obteve %d", total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_CountGrouped_Exists_Sum(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM (SELECT 1 FROM "public"."users" GROUP BY age HAVING COUNT(*) > $1) AS sub`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM "public"."users" WHERE name = $1)`)).
+		WithArgs("Alice").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(age), 0) FROM "public"."users" WHERE age < $1`)).
+		WithArgs(50).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(120.0))
+
+	groups, err := repo.NewQuery(ctx).GroupBy("age").Having("COUNT(*) > ?", 1).Count()
+	if err != nil || groups != 3 {
+		t.Errorf("Count agrupado inesperado: %d, %v", groups, err)
+	}
+
+	exists, err := repo.Where(ctx, "name = ?", "Alice").Exists()
+	if err != nil || !exists {
+		t.Errorf("Exists inesperado: %v, %v", exists, err)
+	}
+
+	sum, err := repo.Where(ctx, "age < ?", 50).Sum("age")
+	if err != nil || sum != 120 {
+		t.Errorf("Sum inesperado: %v, %v", sum, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestPluck(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT name FROM "public"."users" WHERE age > $1 ORDER BY name`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Alice").AddRow("Bob"))
+
+	names, err := Pluck[string](repo.Where(ctx, "age > ?", 18).OrderBy("name"), "name")
+	if err != nil {
+		t.Errorf("Pluck falhou: %s", err)
+	}
+	if len(names) != 2 || names[1] != "Bob" {
+		t.Errorf("Resultado inesperado: %v", names)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestAggregates_RejectExpressions(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	// Nenhuma query chega ao banco: só colunas da entidade são aceitas
+	expr := "age) FROM users; DROP TABLE users; --"
+	if _, err := repo.NewQuery(ctx).Sum(expr); err == nil {
+		t.Error("Sum deveria recusar uma expressão")
+	}
+	if _, err := repo.NewQuery(ctx).Avg("age * 2"); err == nil {
+		t.Error("Avg deveria recusar uma expressão")
+	}
+	var v any
+	if err := repo.NewQuery(ctx).Min("password", &v); err == nil {
+		t.Error("Min deveria recusar uma coluna que não é da entidade")
+	}
+	if err := repo.NewQuery(ctx).Max("", &v); err == nil {
+		t.Error("Max deveria recusar coluna vazia")
+	}
+	if _, err := Pluck[string](repo.NewQuery(ctx), "name || password"); err == nil {
+		t.Error("Pluck deveria recusar uma expressão")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_ScanInto(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	expectedSQL := `SELECT age, COUNT(*) AS total FROM "public"."users" GROUP BY age ORDER BY age`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WillReturnRows(sqlmock.NewRows([]string{"age", "total"}).AddRow(30, 2).AddRow(40, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WillReturnRows(sqlmock.NewRows([]string{"age", "total"}).AddRow(30, 2))
+
+	type ageGroup struct {
+		Age   int   `json:"age"`
+		Total int64 `json:"total"`
+	}
+
+	var groups []ageGroup
+	if err := repo.NewQuery(ctx).GroupBy("age").OrderBy("age").ScanInto(&groups, "age", "COUNT(*) AS total"); err != nil {
+		t.Errorf("ScanInto (struct) falhou: %s", err)
+	}
+	if len(groups) != 2 || groups[0].Total != 2 || groups[1].Age != 40 {
+		t.Errorf("Resultado inesperado: %+v", groups)
+	}
+
+	var maps []map[string]any
+	if err := repo.NewQuery(ctx).GroupBy("age").OrderBy("age").ScanInto(&maps, "age", "COUNT(*) AS total"); err != nil {
+		t.Errorf("ScanInto (map) falhou: %s", err)
+	}
+	if len(maps) != 1 || maps[0]["total"] != int64(2) {
+		t.Errorf("Resultado inesperado: %+v", maps)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
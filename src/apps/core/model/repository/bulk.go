@@ -0,0 +1,409 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// maxParams é o limite de parâmetros por comando do protocolo do Postgres.
+const maxParams = 65535
+
+/*
+InsertMany insere as entidades com INSERT multi-linha (VALUES (...), (...)),
+dividindo em lotes para respeitar o limite de parâmetros. Tudo roda em uma
+única transação (ou na transação do ctx). Valores não preenchidos viram
+DEFAULT e as linhas gravadas são escaneadas de volta nas entidades.
+No SQLite, que não aceita DEFAULT em VALUES, cada entidade tem seu INSERT.
+*/
+func (r *BaseRepository[T, P]) InsertMany(ctx context.Context, items []P) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	cols := items[0].Columns()
+	var inserted int64
+
+	err := r.WithTx(ctx, func(tx *Tx) error {
+		if err := r.prepareInsert(tx, items); err != nil {
+			return err
+		}
+		into := r.insertColumns(items)
+		if len(into) == 0 {
+			return errors.New("nenhuma coluna para inserir")
+		}
+
+		if !r.dialect.BulkDefault() {
+			for _, entity := range items {
+				if err := r.insertRow(tx, entity); err != nil {
+					return err
+				}
+				inserted++
+			}
+			return afterInsertAll(tx, items)
+		}
+
+		batchSize := r.dialect.MaxParams() / len(into)
+		for start := 0; start < len(items); start += batchSize {
+			batch := items[start:min(start+batchSize, len(items))]
+			n, err := r.insertBatch(tx, into, cols, batch)
+			if err != nil {
+				return err
+			}
+			inserted += n
+		}
+		return afterInsertAll(tx, items)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao inserir em lote: %w", translateError(err))
+	}
+	return inserted, nil
+}
+
+// insertBatch executa um INSERT multi-linha e escaneia o RETURNING na mesma ordem.
+func (r *BaseRepository[T, P]) insertBatch(ctx context.Context, into, cols []string, batch []P) (int64, error) {
+	values := make([]any, 0, len(batch)*len(into))
+	rowsSQL := make([]string, 0, len(batch))
+	meta := metaOf(batch[0])
+
+	for _, entity := range batch {
+		colsMap, err := r.getEntityColumnMap(entity)
+		if err != nil {
+			return 0, err
+		}
+
+		cells := make([]string, len(into))
+		for i, col := range into {
+			value := colsMap[col]
+			if isUnsetValue(value) || (meta.isGeneratedKey(col) && isZeroValue(value)) {
+				cells[i] = "DEFAULT"
+				continue
+			}
+			values = append(values, value)
+			cells[i] = r.dialect.Placeholder(len(values))
+		}
+		rowsSQL = append(rowsSQL, "("+strings.Join(cells, ", ")+")")
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES %s",
+		r.getFullTableName(),
+		strings.Join(into, ", "),
+		strings.Join(rowsSQL, ", "),
+	) + r.dialect.Returning(cols)
+
+	rows, err := r.query(ctx, r.executor(ctx), query, values...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var n int64
+	for rows.Next() {
+		if int(n) < len(batch) {
+			if err := batch[n].ScanRow(rows); err != nil {
+				return n, err
+			}
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+// insertRow insere uma única entidade, como Insert, sem os hooks.
+func (r *BaseRepository[T, P]) insertRow(ctx context.Context, entity P) error {
+	into, values, err := r.insertValues(entity)
+	if err != nil {
+		return err
+	}
+	query := r.insertSQL(into, values) + r.dialect.Returning(entity.Columns())
+	return entity.ScanRow(r.queryRow(ctx, r.executor(ctx), query, values...))
+}
+
+/*
+CopyMany grava as entidades com COPY (pq.CopyIn), o caminho mais rápido para
+importações grandes. Diferente de InsertMany, não há RETURNING (as entidades
+não são atualizadas) e valores nulos são gravados como NULL, não DEFAULT.
+A PK gerada é omitida quando nenhuma entidade a traz preenchida.
+Bancos sem COPY (SQLite) usam InsertMany.
+*/
+func (r *BaseRepository[T, P]) CopyMany(ctx context.Context, items []P) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	if !r.dialect.Copy() {
+		return r.InsertMany(ctx, items)
+	}
+
+	err := r.WithTx(ctx, func(tx *Tx) error {
+		if err := r.prepareInsert(tx, items); err != nil {
+			return err
+		}
+		into := r.insertColumns(items)
+
+		copySQL := pq.CopyIn(r.table, into...)
+		if r.schema != "" {
+			copySQL = pq.CopyInSchema(r.schema, r.table, into...)
+		}
+		start := time.Now()
+		stmt, err := tx.PrepareContext(tx, copySQL)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, entity := range items {
+			colsMap, err := r.getEntityColumnMap(entity)
+			if err != nil {
+				return err
+			}
+			values := make([]any, len(into))
+			for i, col := range into {
+				values[i] = colsMap[col]
+			}
+			if _, err := stmt.ExecContext(tx, values...); err != nil {
+				return err
+			}
+		}
+
+		// Exec sem argumentos descarrega o buffer do COPY; o observer recebe
+		// o COPY inteiro como um único comando
+		_, err = stmt.ExecContext(tx)
+		r.observe(tx, start, copySQL, nil, int64(len(items)), err)
+		if err != nil {
+			return err
+		}
+		return afterInsertAll(tx, items)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao copiar em lote: %w", translateError(err))
+	}
+	return int64(len(items)), nil
+}
+
+// prepareInsert executa BeforeInsert e preenche os timestamps de cada entidade.
+func (r *BaseRepository[T, P]) prepareInsert(ctx context.Context, items []P) error {
+	for _, entity := range items {
+		if err := beforeInsert(ctx, entity); err != nil {
+			return err
+		}
+		touchTimestamps(entity, true)
+	}
+	return nil
+}
+
+// insertColumns devolve as colunas de um INSERT em lote, na ordem de Columns().
+// A PK gerada só entra se alguma entidade a trouxer preenchida; colunas
+// readonly/omitinsert nunca entram.
+func (r *BaseRepository[T, P]) insertColumns(items []P) []string {
+	cols := items[0].Columns()
+	meta := metaOf(items[0])
+	into := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if meta.skipOnInsert(col) || (meta.isGeneratedKey(col) && !r.anyKeySet(items, col)) {
+			continue
+		}
+		into = append(into, col)
+	}
+	return into
+}
+
+// anyKeySet informa se alguma entidade traz valor para a coluna de PK.
+func (r *BaseRepository[T, P]) anyKeySet(items []P, col string) bool {
+	for _, entity := range items {
+		colsMap, err := r.getEntityColumnMap(entity)
+		if err == nil && !isZeroValue(colsMap[col]) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Upsert insere a entidade ou, havendo conflito em conflictCols, atualiza
+updateCols com os valores enviados (EXCLUDED). Sem updateCols o conflito é
+ignorado (DO NOTHING) e a entidade não é alterada.
+
+	repo.Upsert(ctx, produto, []string{"codigo"}, []string{"descricao", "preco"})
+*/
+func (r *BaseRepository[T, P]) Upsert(ctx context.Context, entity P, conflictCols, updateCols []string) error {
+	if len(conflictCols) == 0 {
+		return fmt.Errorf("erro no upsert: colunas de conflito não informadas")
+	}
+	cols := entity.Columns()
+	for _, col := range append(slices.Clone(conflictCols), updateCols...) {
+		if !slices.Contains(cols, col) {
+			return fmt.Errorf("erro no upsert: coluna desconhecida %q", col)
+		}
+	}
+	for _, col := range updateCols {
+		if metaOf(entity).readonly[col] {
+			return fmt.Errorf("erro no upsert: coluna %q é somente leitura", col)
+		}
+	}
+
+	if err := beforeInsert(ctx, entity); err != nil {
+		return err
+	}
+	touchTimestamps(entity, true)
+	into, values, err := r.insertValues(entity)
+	if err != nil {
+		return err
+	}
+
+	// Quem atualiza no conflito também renova updated_at
+	if len(updateCols) > 0 && metaOf(entity).hasColumn(ColumnUpdatedAt) && !slices.Contains(updateCols, ColumnUpdatedAt) {
+		updateCols = append(slices.Clone(updateCols), ColumnUpdatedAt)
+	}
+
+	sets := make([]string, 0, len(updateCols)+1)
+	for _, col := range updateCols {
+		sets = append(sets, fmt.Sprintf("%s = %s", col, r.dialect.Excluded(col)))
+	}
+	if version := metaOf(entity).versionColumn; len(sets) > 0 && version != "" && !slices.Contains(updateCols, version) {
+		sets = append(sets, fmt.Sprintf("%s = %s.%s + 1", version, r.getFullTableName(), version))
+	}
+
+	query := r.insertSQL(into, values) + r.dialect.Upsert(conflictCols, sets) + r.dialect.Returning(cols)
+
+	row := r.queryRow(ctx, r.executor(ctx), query, values...)
+	if err := entity.ScanRow(row); err != nil {
+		// DO NOTHING em conflito não devolve linha
+		if errors.Is(err, sql.ErrNoRows) && len(updateCols) == 0 {
+			return nil
+		}
+		return fmt.Errorf("erro no upsert: %w", translateError(err))
+	}
+	return afterInsert(ctx, entity)
+}
+
+/*
+UpdateWhere atualiza as colunas de values em todas as linhas que satisfazem
+as condições do QueryBuilder e retorna o número de linhas afetadas.
+
+	repo.UpdateWhere(repo.Where(ctx, "status = ?", "pendente"), map[string]any{"status": "cancelado"})
+
+Um builder sem condições é recusado para evitar atualizar a tabela inteira
+por engano; use And("1 = 1") se essa for mesmo a intenção. updated_at é
+renovado quando existir e não vier em values, e a coluna de versão é incrementada.
+*/
+func (r *BaseRepository[T, P]) UpdateWhere(query IQueryBuilder[T, P], values map[string]any) (int64, error) {
+	qb, err := r.ownBuilder(query)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("erro ao atualizar em lote: nenhuma coluna informada")
+	}
+	if _, ok := values[ColumnUpdatedAt]; !ok && qb.meta().hasColumn(ColumnUpdatedAt) {
+		values = maps.Clone(values)
+		values[ColumnUpdatedAt] = nowFunc()
+	}
+
+	// Ordena as colunas para gerar sempre o mesmo SQL
+	cols := make([]string, 0, len(values))
+	for col := range values {
+		if !qb.isColumn(col) {
+			return 0, fmt.Errorf("erro ao atualizar em lote: coluna desconhecida %q", col)
+		}
+		if qb.meta().readonly[col] {
+			return 0, fmt.Errorf("erro ao atualizar em lote: coluna %q é somente leitura", col)
+		}
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	// O WHERE é numerado primeiro: um fragmento com $n manual conta com os
+	// seus argumentos começando em $1
+	args := make([]any, 0, len(cols))
+	where := qb.buildWhere(&args)
+
+	sets := make([]string, len(cols))
+	for i, col := range cols {
+		args = append(args, values[col])
+		sets[i] = fmt.Sprintf("%s = %s", col, r.dialect.Placeholder(len(args)))
+	}
+	// Cópias já carregadas dessas linhas passam a ser obsoletas
+	if version := qb.meta().versionColumn; version != "" && !slices.Contains(cols, version) {
+		sets = append(sets, fmt.Sprintf("%s = %s + 1", version, version))
+	}
+
+	sqlStr := fmt.Sprintf("UPDATE %s SET %s WHERE %s", r.getFullTableName(), strings.Join(sets, ", "), where)
+
+	return r.execAffected(qb.ctx, sqlStr, args, "erro ao atualizar em lote")
+}
+
+// DeleteWhere exclui todas as linhas que satisfazem as condições do
+// QueryBuilder e retorna o número de linhas afetadas. Assim como em
+// UpdateWhere, um builder sem condições é recusado. Com deleted_at a
+// exclusão é lógica.
+func (r *BaseRepository[T, P]) DeleteWhere(query IQueryBuilder[T, P]) (int64, error) {
+	qb, err := r.ownBuilder(query)
+	if err != nil {
+		return 0, err
+	}
+	if !qb.meta().softDelete {
+		return r.ForceDeleteWhere(qb)
+	}
+
+	// deleted_at vem depois dos argumentos do WHERE, como em UpdateWhere
+	args := make([]any, 0)
+	where := qb.buildWhere(&args)
+	args = append(args, nowFunc())
+	sqlStr := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s", r.getFullTableName(), ColumnDeletedAt, r.dialect.Placeholder(len(args)), where)
+
+	return r.execAffected(qb.ctx, sqlStr, args, "erro ao excluir em lote")
+}
+
+// ForceDeleteWhere apaga de fato as linhas que satisfazem as condições,
+// mesmo com deleted_at. Combine com OnlyTrashed para expurgar excluídos:
+//
+//	repo.ForceDeleteWhere(repo.NewQuery(ctx).OnlyTrashed().And("deleted_at < ?", limite))
+func (r *BaseRepository[T, P]) ForceDeleteWhere(query IQueryBuilder[T, P]) (int64, error) {
+	qb, err := r.ownBuilder(query)
+	if err != nil {
+		return 0, err
+	}
+
+	args := make([]any, 0)
+	where := qb.buildWhere(&args)
+	sqlStr := fmt.Sprintf("DELETE FROM %s WHERE %s", r.getFullTableName(), where)
+
+	return r.execAffected(qb.ctx, sqlStr, args, "erro ao excluir em lote")
+}
+
+// ownBuilder valida que o builder foi criado por este repositório e tem condições.
+func (r *BaseRepository[T, P]) ownBuilder(query IQueryBuilder[T, P]) (*QueryBuilder[T, P], error) {
+	qb, ok := query.(*QueryBuilder[T, P])
+	if !ok || qb.repo != r {
+		return nil, fmt.Errorf("QueryBuilder não pertence a este repositório")
+	}
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if len(qb.wheres) == 0 {
+		return nil, fmt.Errorf("operação em lote sem condições recusada")
+	}
+	return qb, nil
+}
+
+// execAffected executa o comando e devolve RowsAffected.
+func (r *BaseRepository[T, P]) execAffected(ctx context.Context, query string, args []any, errPrefix string) (int64, error) {
+	result, err := r.exec(ctx, r.executor(ctx), query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", errPrefix, translateError(err))
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	return affected, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestInsertMany(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	users := []*MockUser{
+		{Name: sql.NullString{String: "Ana", Valid: true}, Age: 20},
+		{Age: 30}, // name vira DEFAULT
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."users" (name, age) VALUES ($1, $2), (DEFAULT, $3) RETURNING id, name, age`)).
+		WithArgs("Ana", 20, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(1, "Ana", 20).AddRow(2, nil, 30))
+	mock.ExpectCommit()
+
+	n, err := repo.InsertMany(ctx, users)
+	if err != nil {
+		t.Fatalf("InsertMany falhou: %s", err)
+	}
+	if n != 2 || users[0].ID != 1 || users[1].ID != 2 {
+		t.Errorf("Resultado inesperado: n=%d, %+v %+v", n, users[0], users[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestCopyMany(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectBegin()
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(`COPY "public"."users" ("name", "age") FROM STDIN`))
+	prep.ExpectExec().WithArgs("Ana", 20).WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WithArgs("Bia", 25).WillReturnResult(sqlmock.NewResult(0, 0))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	n, err := repo.CopyMany(ctx, []*MockUser{
+		{Name: sql.NullString{String: "Ana", Valid: true}, Age: 20},
+		{Name: sql.NullString{String: "Bia", Valid: true}, Age: 25},
+	})
+	if err != nil {
+		t.Fatalf("CopyMany falhou: %s", err)
+	}
+	if n != 2 {
+		t.Errorf("Esperava 2 linhas, obteve %d", n)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	expectedSQL := `INSERT INTO "public"."users" (id, name, age) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name RETURNING id, name, age`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WithArgs(int64(9), "Novo", 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(9, "Novo", 44))
+
+	user := &MockUser{ID: 9, Name: sql.NullString{String: "Novo", Valid: true}, Age: 50}
+	if err := repo.Upsert(ctx, user, []string{"id"}, []string{"name"}); err != nil {
+		t.Errorf("Upsert falhou: %s", err)
+	}
+	if user.Age != 44 {
+		t.Errorf("Esperava a idade já gravada (44), obteve %d", user.Age)
+	}
+
+	// DO NOTHING em conflito não é erro
+	mock.ExpectQuery(regexp.QuoteMeta(`ON CONFLICT (id) DO NOTHING`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}))
+	if err := repo.Upsert(ctx, user, []string{"id"}, nil); err != nil {
+		t.Errorf("Upsert (DO NOTHING) falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestUpdateWhereAndDeleteWhere(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "public"."users" SET age = $4, name = $5 WHERE age < $1 AND id IN ($2, $3)`)).
+		WithArgs(18, 1, 2, 18, "menor").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."users" WHERE name IS NULL`)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	// Um fragmento com $n manual continua apontando para os seus argumentos
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "public"."users" SET name = $2 WHERE id = $1`)).
+		WithArgs(7, "x").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	updated, err := repo.UpdateWhere(
+		repo.Where(ctx, "age < ?", 18).In("id", 1, 2),
+		map[string]any{"name": "menor", "age": 18},
+	)
+	if err != nil || updated != 2 {
+		t.Errorf("UpdateWhere inesperado: %d, %v", updated, err)
+	}
+
+	deleted, err := repo.DeleteWhere(repo.NewQuery(ctx).IsNull("name"))
+	if err != nil || deleted != 3 {
+		t.Errorf("DeleteWhere inesperado: %d, %v", deleted, err)
+	}
+	if _, err := repo.UpdateWhere(repo.Where(ctx, "id = $1", 7), map[string]any{"name": "x"}); err != nil {
+		t.Errorf("UpdateWhere com $n manual falhou: %v", err)
+	}
+
+	// Sem condições a operação é recusada antes de chegar ao banco
+	if _, err := repo.DeleteWhere(repo.NewQuery(ctx)); err == nil {
+		t.Error("Esperava erro para DeleteWhere sem condições")
+	}
+	if _, err := repo.UpdateWhere(repo.Where(ctx, "id = ?", 1), map[string]any{"senha": "x"}); err == nil {
+		t.Error("Esperava erro para coluna desconhecida")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+Condition é um trecho do WHERE que pode ser combinado com outros.
+Os fragmentos usam '?' como placeholder e são renumerados para $1, $2...
+(?1, ?2... no SQLite) na hora de montar a query, então podem ser
+compostos livremente:
+
+	AnyOf(Expr("status = ?", "ativo"), In("tipo", 1, 2)).
+	  -> (status = $1 OR tipo IN ($2, $3))
+
+Use '??' para um '?' literal (ex: operadores jsonb). Fragmentos que já
+usam $n são mantidos como estão (numeração manual); o QueryBuilder recusa
+misturar os dois estilos, já que a renumeração dos '?' não enxerga os $n.
+*/
+type Condition struct {
+	fragment string
+	args     []any
+	op       string // "AND" ou "OR" quando for um grupo
+	children []Condition
+	negate   bool
+	ilike    string // coluna do ILike, montado conforme o dialeto
+}
+
+// Expr cria uma condição a partir de um fragmento SQL com placeholders '?'.
+func Expr(fragment string, args ...any) Condition {
+	return Condition{fragment: fragment, args: args}
+}
+
+// AllOf agrupa as condições com AND: (a AND b AND ...).
+func AllOf(conds ...Condition) Condition {
+	return Condition{op: "AND", children: conds}
+}
+
+// AnyOf agrupa as condições com OR: (a OR b OR ...).
+func AnyOf(conds ...Condition) Condition {
+	return Condition{op: "OR", children: conds}
+}
+
+// Not nega a condição: NOT (c).
+func Not(c Condition) Condition {
+	return Condition{op: "AND", children: []Condition{c}, negate: true}
+}
+
+// In cria "col IN (...)". Aceita valores avulsos ou um único slice.
+// Uma lista vazia nunca casa com nenhuma linha.
+func In(col string, values ...any) Condition {
+	values = flattenArgs(values)
+	if len(values) == 0 {
+		return Expr("1 = 0")
+	}
+	return Expr(fmt.Sprintf("%s IN (%s)", col, placeholderList(len(values))), values...)
+}
+
+// NotIn cria "col NOT IN (...)". Uma lista vazia casa com todas as linhas.
+func NotIn(col string, values ...any) Condition {
+	values = flattenArgs(values)
+	if len(values) == 0 {
+		return Expr("1 = 1")
+	}
+	return Expr(fmt.Sprintf("%s NOT IN (%s)", col, placeholderList(len(values))), values...)
+}
+
+// IsNull cria "col IS NULL".
+func IsNull(col string) Condition {
+	return Expr(col + " IS NULL")
+}
+
+// NotNull cria "col IS NOT NULL".
+func NotNull(col string) Condition {
+	return Expr(col + " IS NOT NULL")
+}
+
+// Between cria "col BETWEEN from AND to".
+func Between(col string, from, to any) Condition {
+	return Expr(col+" BETWEEN ? AND ?", from, to)
+}
+
+// Like cria "col LIKE pattern".
+func Like(col string, pattern any) Condition {
+	return Expr(col+" LIKE ?", pattern)
+}
+
+// ILike cria "col ILIKE pattern" (sem diferenciar maiúsculas).
+// No SQLite vira LIKE, que já ignora maiúsculas.
+func ILike(col string, pattern any) Condition {
+	return Condition{args: []any{pattern}, ilike: col}
+}
+
+// build escreve a condição em SQL, numerando os placeholders a partir
+// do total de argumentos já acumulados em args.
+func (c Condition) build(d Dialect, args *[]any) string {
+	var sql string
+	switch {
+	case c.ilike != "":
+		sql = rewritePlaceholders(d, d.ILike(c.ilike), c.args, args)
+	case c.op == "":
+		sql = rewritePlaceholders(d, c.fragment, c.args, args)
+	default:
+		parts := make([]string, 0, len(c.children))
+		for _, child := range c.children {
+			if part := child.build(d, args); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		sql = strings.Join(parts, " "+c.op+" ")
+		if len(parts) > 1 {
+			sql = "(" + sql + ")"
+		}
+	}
+
+	if c.negate && sql != "" {
+		return "NOT (" + sql + ")"
+	}
+	return sql
+}
+
+// rewritePlaceholders troca cada '?' fora de aspas pelo parâmetro n do
+// dialeto ($n, ?n) e acumula os argumentos correspondentes. Argumentos sem
+// '?' (fragmentos com $n manual) são apenas acrescentados na ordem recebida.
+func rewritePlaceholders(d Dialect, fragment string, fragArgs []any, args *[]any) string {
+	var out strings.Builder
+	next := 0
+	var quote rune
+
+	runes := []rune(fragment)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?' && i+1 < len(runes) && runes[i+1] == '?':
+			out.WriteRune('?')
+			i++
+			continue
+		case r == '?' && next < len(fragArgs):
+			*args = append(*args, fragArgs[next])
+			next++
+			out.WriteString(d.Placeholder(len(*args)))
+			continue
+		}
+		out.WriteRune(r)
+	}
+
+	*args = append(*args, fragArgs[next:]...)
+	return out.String()
+}
+
+// placeholderStyle informa se a condição usa '?' renumerados e/ou $n
+// manuais (fora de aspas), olhando também os grupos.
+func (c Condition) placeholderStyle() (question, manual bool) {
+	if c.ilike != "" {
+		return true, false
+	}
+	for _, child := range c.children {
+		q, m := child.placeholderStyle()
+		question, manual = question || q, manual || m
+	}
+
+	var quote rune
+	runes := []rune(c.fragment)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?' && i+1 < len(runes) && runes[i+1] == '?':
+			i++
+		case r == '?':
+			// Sem argumentos o '?' fica literal (ver rewritePlaceholders)
+			question = question || len(c.args) > 0
+		case r == '$' && i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '9':
+			manual = true
+		}
+	}
+	return question, manual
+}
+
+// placeholderList gera "?, ?, ?" com n posições.
+func placeholderList(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// flattenArgs expande um único argumento do tipo slice (exceto []byte)
+// para permitir In("id", ids) além de In("id", 1, 2, 3).
+func flattenArgs(values []any) []any {
+	if len(values) != 1 {
+		return values
+	}
+	rv := reflect.ValueOf(values[0])
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return values
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package repository
+
+import "testing"
+
+func TestCondition_Build(t *testing.T) {
+	cases := []struct {
+		name     string
+		cond     Condition
+		wantSQL  string
+		wantArgs int
+	}{
+		{"expr", Expr("age > ? AND age < ?", 18, 65), "age > $1 AND age < $2", 2},
+		{"in", In("id", 1, 2, 3), "id IN ($1, $2, $3)", 3},
+		{"in slice", In("id", []int64{4, 5}), "id IN ($1, $2)", 2},
+		{"in vazio", In("id"), "1 = 0", 0},
+		{"not in vazio", NotIn("id", []string{}), "1 = 1", 0},
+		{"null", IsNull("deleted_at"), "deleted_at IS NULL", 0},
+		{"between", Between("age", 1, 9), "age BETWEEN $1 AND $2", 2},
+		{"any of", AnyOf(Like("name", "A%"), NotNull("email")), "(name LIKE $1 OR email IS NOT NULL)", 1},
+		{"not", Not(AllOf(Expr("a = ?", 1), ILike("b", "%x%"))), "NOT ((a = $1 AND b ILIKE $2))", 2},
+		{"aspas e escape", Expr("meta ?? 'key?' AND x = ?", 1), "meta ? 'key?' AND x = $1", 1},
+		{"numeração manual", Expr("id = $1", 7), "id = $1", 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var args []any
+			got := tc.cond.build(Postgres, &args)
+			if got != tc.wantSQL {
+				t.Errorf("SQL esperado %q, obtido %q", tc.wantSQL, got)
+			}
+			if len(args) != tc.wantArgs {
+				t.Errorf("Esperava %d argumentos, obteve %d", tc.wantArgs, len(args))
+			}
+		})
+	}
+}
+
+func TestQueryBuilder_MixedPlaceholders(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	// Nenhuma query chega ao banco: "name = $1 AND age > $1" estaria errado
+	mixed := []IQueryBuilder[MockUser, *MockUser]{
+		repo.Where(ctx, "name = ?", "Ana").And("age > $1", 3),
+		repo.Where(ctx, "age > $1", 3).AndCond(ILike("name", "a%")),
+		repo.Where(ctx, "age > $1", 3).OrCond(AnyOf(Expr("name = ?", "Ana"))),
+		repo.Where(ctx, "name = ?", "Ana").GroupBy("age").Having("COUNT(*) > $1", 1),
+	}
+	for i, qb := range mixed {
+		if _, err := qb.Query(); err == nil {
+			t.Errorf("caso %d: esperava erro por misturar '?' e $n", i)
+		}
+	}
+
+	// '??', '?' sem argumento e $1 entre aspas não contam como placeholder
+	for _, c := range []Condition{
+		Expr("meta ?? 'k' AND id = $1", 1),
+		Expr("nota = '$1' AND id = ?", 1),
+		Expr("meta ? 'k'"),
+	} {
+		if q, m := c.placeholderStyle(); q && m {
+			t.Errorf("%q não mistura estilos", c.fragment)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"modernc.org/sqlite"
+)
+
+/*
+Dialect isola as diferenças de SQL entre os bancos suportados. O
+repositório escolhe o dialeto pelo driver do *sql.DB (ver DialectOf):
+
+	repo := NewUsuarioRepository(db) // Postgres ou SQLite, conforme o DATABASE_URL
+
+Postgres é o padrão; SQLite atende o uso offline do desktop. Diferenças
+que o SQLite não cobre (COPY, cursores no servidor, DEFAULT em INSERT
+multi-linha) têm alternativa no próprio repositório.
+*/
+type Dialect interface {
+	// Name identifica o dialeto ("postgres" ou "sqlite").
+	Name() string
+	// Placeholder devolve o parâmetro de número n (1-based).
+	Placeholder(n int) string
+	// Quote coloca o identificador entre aspas.
+	Quote(ident string) string
+	// Table formata o nome completo da tabela.
+	Table(schema, table string) string
+	// Returning monta a cláusula que devolve as colunas gravadas.
+	Returning(cols []string) string
+	// Upsert monta o "ON CONFLICT" com as atribuições de sets, que podem
+	// referenciar os valores enviados com Excluded. Sem sets, DO NOTHING.
+	Upsert(conflictCols, sets []string) string
+	// Excluded referencia o valor enviado para col no Upsert.
+	Excluded(col string) string
+	// ILike monta a comparação de col com um padrão '?' sem diferenciar maiúsculas.
+	ILike(col string) string
+	// LimitOffset monta LIMIT/OFFSET (zero omite a cláusula).
+	LimitOffset(limit, offset uint64) string
+	// MaxParams é o limite de parâmetros por comando.
+	MaxParams() int
+	// BulkDefault informa se DEFAULT é aceito dentro de um INSERT multi-linha.
+	BulkDefault() bool
+	// ServerCursor informa se há cursores no servidor (DECLARE/FETCH).
+	ServerCursor() bool
+	// Copy informa se o banco aceita COPY (pq.CopyIn).
+	Copy() bool
+}
+
+// Dialetos suportados.
+var (
+	Postgres Dialect = postgresDialect{}
+	SQLite   Dialect = sqliteDialect{}
+)
+
+// DialectOf escolhe o dialeto pelo driver da conexão. Conexões nulas ou de
+// outros drivers (inclusive sqlmock nos testes) usam Postgres.
+func DialectOf(db *sql.DB) Dialect {
+	if db == nil {
+		return Postgres
+	}
+	if _, ok := db.Driver().(*sqlite.Driver); ok {
+		return SQLite
+	}
+	return Postgres
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (postgresDialect) Quote(ident string) string { return quoteIdent(ident) }
+
+func (postgresDialect) Table(schema, table string) string {
+	if schema != "" {
+		return quoteIdent(schema) + "." + quoteIdent(table)
+	}
+	return quoteIdent(table)
+}
+
+func (postgresDialect) Returning(cols []string) string { return returningClause(cols) }
+
+func (postgresDialect) Upsert(conflictCols, sets []string) string {
+	return onConflict(conflictCols, sets)
+}
+
+func (postgresDialect) Excluded(col string) string { return "EXCLUDED." + col }
+
+func (postgresDialect) ILike(col string) string { return col + " ILIKE ?" }
+
+func (postgresDialect) LimitOffset(limit, offset uint64) string {
+	var out string
+	if limit > 0 {
+		out += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	if offset > 0 {
+		out += fmt.Sprintf(" OFFSET %d", offset)
+	}
+	return out
+}
+
+// MaxParams segue o limite do protocolo do Postgres.
+func (postgresDialect) MaxParams() int { return maxParams }
+
+func (postgresDialect) BulkDefault() bool  { return true }
+func (postgresDialect) ServerCursor() bool { return true }
+func (postgresDialect) Copy() bool         { return true }
+
+// sqliteDialect usa parâmetros numerados (?1, ?2...) e ignora o schema:
+// no SQLite ele nomeia um banco anexado, não um namespace como no Postgres.
+// RETURNING e ON CONFLICT exigem SQLite 3.35+ (o driver embutido atende).
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Placeholder(n int) string { return fmt.Sprintf("?%d", n) }
+
+func (sqliteDialect) Quote(ident string) string { return quoteIdent(ident) }
+
+func (sqliteDialect) Table(_, table string) string { return quoteIdent(table) }
+
+func (sqliteDialect) Returning(cols []string) string { return returningClause(cols) }
+
+func (sqliteDialect) Upsert(conflictCols, sets []string) string {
+	return onConflict(conflictCols, sets)
+}
+
+func (sqliteDialect) Excluded(col string) string { return "excluded." + col }
+
+// ILike usa LIKE, que no SQLite já ignora maiúsculas (apenas ASCII).
+func (sqliteDialect) ILike(col string) string { return col + " LIKE ?" }
+
+// LimitOffset usa LIMIT -1 quando só há OFFSET: o SQLite não aceita OFFSET sozinho.
+func (sqliteDialect) LimitOffset(limit, offset uint64) string {
+	switch {
+	case limit > 0 && offset > 0:
+		return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	case limit > 0:
+		return fmt.Sprintf(" LIMIT %d", limit)
+	case offset > 0:
+		return fmt.Sprintf(" LIMIT -1 OFFSET %d", offset)
+	}
+	return ""
+}
+
+// MaxParams segue SQLITE_MAX_VARIABLE_NUMBER (32766 desde o SQLite 3.32).
+func (sqliteDialect) MaxParams() int { return 32766 }
+
+func (sqliteDialect) BulkDefault() bool  { return false }
+func (sqliteDialect) ServerCursor() bool { return false }
+func (sqliteDialect) Copy() bool         { return false }
+
+// quoteIdent coloca o identificador entre aspas duplas, escapando as internas.
+func quoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// onConflict monta "ON CONFLICT (cols) DO UPDATE SET ..." ou "DO NOTHING",
+// sintaxe comum ao Postgres e ao SQLite.
+func onConflict(conflictCols, sets []string) string {
+	action := "DO NOTHING"
+	if len(sets) > 0 {
+		action = "DO UPDATE SET " + strings.Join(sets, ", ")
+	}
+	return fmt.Sprintf(" ON CONFLICT (%s) %s", strings.Join(conflictCols, ", "), action)
+}
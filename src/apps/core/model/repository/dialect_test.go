@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// setupSQLite cria um repo sobre um SQLite em memória (sem mock: o driver é embutido).
+func setupSQLite(t *testing.T) *BaseRepository[MockUser, *MockUser] {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("falha ao abrir sqlite: %s", err)
+	}
+	// Cada conexão de :memory: é um banco diferente
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT UNIQUE, age INTEGER NOT NULL DEFAULT 0)`); err != nil {
+		t.Fatalf("falha ao criar tabela: %s", err)
+	}
+	return NewBaseRepository[MockUser](db, "users", "public")
+}
+
+func TestDialectOf(t *testing.T) {
+	repo, _, _ := setup(t)
+	defer repo.GetDB().Close()
+	if repo.Dialect() != Postgres {
+		t.Errorf("sqlmock deveria usar Postgres, obteve %s", repo.Dialect().Name())
+	}
+	if got := setupSQLite(t).Dialect(); got != SQLite {
+		t.Errorf("Esperava SQLite, obteve %s", got.Name())
+	}
+}
+
+func TestDialect_SQL(t *testing.T) {
+	if got := SQLite.Table("public", "users"); got != `"users"` {
+		t.Errorf("SQLite deveria ignorar o schema, obteve %s", got)
+	}
+	if got := SQLite.LimitOffset(0, 20); got != " LIMIT -1 OFFSET 20" {
+		t.Errorf("OFFSET sem LIMIT inválido no SQLite: %q", got)
+	}
+
+	var args []any
+	got := AllOf(Expr("age > ?", 18), ILike("name", "an%")).build(SQLite, &args)
+	if want := "(age > ?1 AND name LIKE ?2)"; got != want {
+		t.Errorf("Esperava %q, obteve %q", want, got)
+	}
+}
+
+func TestSQLite_CRUD(t *testing.T) {
+	repo := setupSQLite(t)
+	ctx := t.Context()
+
+	ana := &MockUser{Name: sql.NullString{String: "Ana", Valid: true}, Age: 20}
+	if err := repo.Insert(ctx, ana); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if ana.ID == 0 {
+		t.Fatal("Insert deveria preencher o ID pelo RETURNING")
+	}
+
+	ana.Age = 21
+	if err := repo.Update(ctx, ana); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	n, err := repo.InsertMany(ctx, []*MockUser{
+		{Name: sql.NullString{String: "Bruno", Valid: true}},
+		{Name: sql.NullString{String: "Carla", Valid: true}, Age: 35},
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("InsertMany: n=%d err=%v", n, err)
+	}
+
+	found, err := repo.NewQuery(ctx).ILike("name", "ANA").First()
+	if err != nil || found.Age != 21 {
+		t.Fatalf("First: %+v %v", found, err)
+	}
+
+	page, err := repo.NewQuery(ctx).OrderAsc("id").Offset(1).Query()
+	if err != nil || len(page) != 2 || page[0].Name.String != "Bruno" {
+		t.Fatalf("Offset: %+v %v", page, err)
+	}
+
+	var names []string
+	err = repo.NewQuery(ctx).OrderAsc("id").Cursor(2).Each(func(u *MockUser) error {
+		names = append(names, u.Name.String)
+		return nil
+	})
+	if err != nil || len(names) != 3 || names[2] != "Carla" {
+		t.Fatalf("Cursor: %v %v", names, err)
+	}
+
+	if err := repo.Delete(ctx, ana); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Where(ctx, "id = ?", ana.ID).First(); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Esperava ErrNotFound, obteve %v", err)
+	}
+}
+
+func TestSQLite_UpsertAndConstraint(t *testing.T) {
+	repo := setupSQLite(t)
+	ctx := t.Context()
+
+	if err := repo.Insert(ctx, &MockUser{Name: sql.NullString{String: "Ana", Valid: true}, Age: 20}); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	dup := &MockUser{Name: sql.NullString{String: "Ana", Valid: true}, Age: 30}
+	err := repo.Insert(ctx, dup)
+	var cErr *ConstraintError
+	if !errors.Is(err, ErrUniqueViolation) || !errors.As(err, &cErr) || cErr.Column != "name" || cErr.Table != "users" {
+		t.Fatalf("Esperava ErrUniqueViolation em users.name, obteve %v", err)
+	}
+
+	if err := repo.Upsert(ctx, dup, []string{"name"}, []string{"age"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if dup.ID == 0 || dup.Age != 30 {
+		t.Fatalf("Upsert deveria atualizar a linha existente: %+v", dup)
+	}
+	if total, _ := repo.NewQuery(ctx).Count(); total != 1 {
+		t.Errorf("Esperava 1 linha, obteve %d", total)
+	}
+}
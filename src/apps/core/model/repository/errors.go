@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+/*
+Erros do repositório, para comparar com errors.Is em vez do texto:
+
+	if errors.Is(err, repository.ErrNotFound) { ... }
+
+	var cErr *repository.ConstraintError
+	if errors.As(err, &cErr) {
+		// cErr.Constraint, cErr.Column
+	}
+
+Violações de constraint chegam como *ConstraintError, que também desembrulha
+para o erro original do driver (*pq.Error ou *sqlite.Error).
+*/
+var (
+	ErrNotFound            = errors.New("registro não encontrado")
+	ErrUniqueViolation     = errors.New("registro duplicado")
+	ErrForeignKeyViolation = errors.New("referência a registro inexistente ou em uso")
+	ErrCheckViolation      = errors.New("valor fora das regras da tabela")
+	ErrStale               = errors.New("registro alterado por outro usuário")
+	ErrStaleEntity         = ErrStale // mesmo erro: errors.Is vale para os dois nomes
+)
+
+// Códigos SQLSTATE do Postgres tratados pelo repositório.
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqCheckViolation      = "23514"
+)
+
+// ConstraintError descreve a violação de uma constraint do banco.
+type ConstraintError struct {
+	Kind       error // ErrUniqueViolation, ErrForeignKeyViolation ou ErrCheckViolation
+	Table      string
+	Constraint string
+	Column     string
+	Detail     string
+	cause      error
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("%s: %s (coluna %s)", e.Kind, e.Constraint, e.Column)
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, e.Constraint)
+}
+
+// Unwrap permite errors.Is com o sentinela e errors.As com o erro do driver.
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.Kind, e.cause}
+}
+
+// keyDetail captura as colunas do detalhe do Postgres: "Key (email)=(a@b.c) already exists."
+var keyDetail = regexp.MustCompile(`\(([^)]+)\)=`)
+
+// translateError converte erros do driver nos erros do repositório.
+// Erros sem correspondência são devolvidos como vieram.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return translateSQLiteError(err, sqliteErr)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	var kind error
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		kind = ErrUniqueViolation
+	case pqForeignKeyViolation:
+		kind = ErrForeignKeyViolation
+	case pqCheckViolation:
+		kind = ErrCheckViolation
+	default:
+		return err
+	}
+
+	column := pqErr.Column
+	if m := keyDetail.FindStringSubmatch(pqErr.Detail); column == "" && m != nil {
+		column = m[1]
+	}
+	return &ConstraintError{
+		Kind:       kind,
+		Table:      pqErr.Table,
+		Constraint: pqErr.Constraint,
+		Column:     column,
+		Detail:     pqErr.Detail,
+		cause:      pqErr,
+	}
+}
+
+// sqliteConstraint captura o detalhe do SQLite: "UNIQUE constraint failed: users.email".
+var sqliteConstraint = regexp.MustCompile(`constraint failed: ([^:(]+?)(?: \(\d+\))?$`)
+
+// translateSQLiteError faz o mesmo que translateError para os códigos
+// estendidos de constraint do SQLite. Como o SQLite não informa o nome da
+// constraint, Constraint recebe o detalhe ("users.email").
+func translateSQLiteError(err error, sqliteErr *sqlite.Error) error {
+	var kind error
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		kind = ErrUniqueViolation
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		kind = ErrForeignKeyViolation
+	case sqlite3.SQLITE_CONSTRAINT_CHECK:
+		kind = ErrCheckViolation
+	default:
+		return err
+	}
+
+	cErr := &ConstraintError{Kind: kind, Detail: sqliteErr.Error(), cause: sqliteErr}
+	m := sqliteConstraint.FindStringSubmatch(sqliteErr.Error())
+	if m == nil {
+		return cErr
+	}
+	cErr.Constraint = m[1]
+	if kind == ErrCheckViolation {
+		return cErr
+	}
+
+	// "tabela.col1, tabela.col2"
+	cols := strings.Split(m[1], ", ")
+	for i, col := range cols {
+		if table, name, ok := strings.Cut(col, "."); ok {
+			cErr.Table, cols[i] = table, name
+		}
+	}
+	cErr.Column = strings.Join(cols, ", ")
+	return cErr
+}
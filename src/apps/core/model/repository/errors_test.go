@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   error
+		column string
+	}{
+		{"sem linhas", sql.ErrNoRows, ErrNotFound, ""},
+		{"unique", &pq.Error{Code: "23505", Constraint: "users_email_key", Detail: "Key (email)=(a@b.c) already exists."}, ErrUniqueViolation, "email"},
+		{"fk", &pq.Error{Code: "23503", Constraint: "pedidos_usuario_id_fkey", Detail: "Key (usuario_id)=(9) is not present in table \"users\"."}, ErrForeignKeyViolation, "usuario_id"},
+		{"check", &pq.Error{Code: "23514", Constraint: "users_age_check", Column: "age"}, ErrCheckViolation, "age"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Esperava %v, obteve %v", tt.want, err)
+			}
+
+			var cErr *ConstraintError
+			if errors.As(err, &cErr) && cErr.Column != tt.column {
+				t.Errorf("Esperava coluna %q, obteve %q", tt.column, cErr.Column)
+			}
+		})
+	}
+
+	other := &pq.Error{Code: "42P01"}
+	if translateError(other) != other {
+		t.Error("Erros sem correspondência devem ser devolvidos como vieram")
+	}
+}
+
+func TestInsert_UniqueViolation(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."users"`)).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "users_name_key", Detail: "Key (name)=(Ana) already exists."})
+
+	err := repo.Insert(ctx, &MockUser{Name: sql.NullString{String: "Ana", Valid: true}, Age: 20})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("Esperava ErrUniqueViolation, obteve %v", err)
+	}
+
+	// O erro original do driver continua acessível
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Constraint != "users_name_key" {
+		t.Errorf("Esperava acessar o *pq.Error, obteve %v", err)
+	}
+}
+
+func TestFirst_NotFound(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" WHERE id = $1 LIMIT 1`)).
+		WithArgs(99).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}))
+
+	if _, err := repo.Where(ctx, "id = ?", 99).First(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Esperava ErrNotFound, obteve %v", err)
+	}
+}
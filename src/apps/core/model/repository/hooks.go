@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"deskapp/src/apps/core/model/entities"
+)
+
+// Despachantes dos hooks opcionais declarados em entities (BeforeInserter, ...).
+// UpdateWhere e DeleteWhere operam direto no banco, sem carregar entidades,
+// e por isso não disparam hooks.
+
+func beforeInsert(ctx context.Context, entity entities.Entity) error {
+	if h, ok := entity.(entities.BeforeInserter); ok {
+		return h.BeforeInsert(ctx)
+	}
+	return nil
+}
+
+func afterInsert(ctx context.Context, entity entities.Entity) error {
+	if h, ok := entity.(entities.AfterInserter); ok {
+		return h.AfterInsert(ctx)
+	}
+	return nil
+}
+
+// afterInsertAll dispara AfterInsert em cada entidade de um INSERT em lote.
+func afterInsertAll[P entities.Entity](ctx context.Context, items []P) error {
+	for _, entity := range items {
+		if err := afterInsert(ctx, entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func beforeUpdate(ctx context.Context, entity entities.Entity) error {
+	if h, ok := entity.(entities.BeforeUpdater); ok {
+		return h.BeforeUpdate(ctx)
+	}
+	return nil
+}
+
+func afterUpdate(ctx context.Context, entity entities.Entity) error {
+	if h, ok := entity.(entities.AfterUpdater); ok {
+		return h.AfterUpdate(ctx)
+	}
+	return nil
+}
+
+func beforeDelete(ctx context.Context, entity entities.Entity) error {
+	if h, ok := entity.(entities.BeforeDeleter); ok {
+		return h.BeforeDelete(ctx)
+	}
+	return nil
+}
+
+func afterFind(ctx context.Context, entity entities.Entity) error {
+	if h, ok := entity.(entities.AfterFinder); ok {
+		return h.AfterFind(ctx)
+	}
+	return nil
+}
+
+// afterFindAll chama AfterFind em cada item lido, parando no primeiro erro.
+func afterFindAll[T any, P interface { *T; entities.Entity }](ctx context.Context, items []*T) error {
+	for _, item := range items {
+		if err := afterFind(ctx, P(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"deskapp/src/apps/core/model/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// MockCliente normaliza o e-mail, valida e registra os hooks chamados
+type MockCliente struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+
+	calls []string
+	inTx  bool
+}
+
+func (m *MockCliente) Columns() []string { return []string{"id", "email"} }
+
+func (m *MockCliente) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.Email)
+}
+
+func (m *MockCliente) BeforeInsert(ctx context.Context) error {
+	m.calls = append(m.calls, "BeforeInsert")
+	_, m.inTx = TxFromContext(ctx)
+	m.Email = strings.ToLower(strings.TrimSpace(m.Email))
+	if m.Email == "" {
+		return errors.New("e-mail obrigatório")
+	}
+	return nil
+}
+
+func (m *MockCliente) AfterInsert(ctx context.Context) error {
+	m.calls = append(m.calls, "AfterInsert")
+	return nil
+}
+
+func (m *MockCliente) BeforeUpdate(ctx context.Context) error {
+	m.calls = append(m.calls, "BeforeUpdate")
+	return nil
+}
+
+func (m *MockCliente) AfterUpdate(ctx context.Context) error {
+	m.calls = append(m.calls, "AfterUpdate")
+	return nil
+}
+
+func (m *MockCliente) BeforeDelete(ctx context.Context) error {
+	m.calls = append(m.calls, "BeforeDelete")
+	return nil
+}
+
+func (m *MockCliente) AfterFind(ctx context.Context) error {
+	m.calls = append(m.calls, "AfterFind")
+	return nil
+}
+
+func setupClientes(t *testing.T) (*BaseRepository[MockCliente, *MockCliente], sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewBaseRepository[MockCliente](db, "clientes", "public"), mock
+}
+
+func TestHooks_InsertUpdateDelete(t *testing.T) {
+	repo, mock := setupClientes(t)
+	ctx := t.Context()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."clientes" (email) VALUES ($1)`)).
+		WithArgs("ana@exemplo.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "ana@exemplo.com"))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "public"."clientes" SET email = $1 WHERE id = $2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "ana@exemplo.com"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."clientes" WHERE id = $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	cliente := &MockCliente{Email: "  Ana@Exemplo.com "}
+	if err := repo.Insert(ctx, cliente); err != nil {
+		t.Fatalf("Insert falhou: %s", err)
+	}
+	if err := repo.Update(ctx, cliente); err != nil {
+		t.Fatalf("Update falhou: %s", err)
+	}
+	if err := repo.Delete(ctx, cliente); err != nil {
+		t.Fatalf("Delete falhou: %s", err)
+	}
+
+	expected := "BeforeInsert,AfterInsert,BeforeUpdate,AfterUpdate,BeforeDelete"
+	if got := strings.Join(cliente.calls, ","); got != expected {
+		t.Errorf("Esperava hooks %s, obteve %s", expected, got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestHooks_BeforeInsertCancels(t *testing.T) {
+	repo, mock := setupClientes(t)
+
+	// Nenhum comando deve chegar ao banco
+	if err := repo.Insert(t.Context(), &MockCliente{Email: "   "}); err == nil {
+		t.Error("Esperava o erro de validação do BeforeInsert")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestHooks_InsertManyRunsInTransaction(t *testing.T) {
+	repo, mock := setupClientes(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."clientes" (email) VALUES ($1), ($2)`)).
+		WithArgs("a@x.com", "b@x.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "a@x.com").AddRow(2, "b@x.com"))
+	mock.ExpectCommit()
+
+	items := []*MockCliente{{Email: "A@x.com"}, {Email: "B@x.com"}}
+	if _, err := repo.InsertMany(t.Context(), items); err != nil {
+		t.Fatalf("InsertMany falhou: %s", err)
+	}
+	for _, item := range items {
+		if !item.inTx || strings.Join(item.calls, ",") != "BeforeInsert,AfterInsert" {
+			t.Errorf("Hooks incorretos: inTx=%v calls=%v", item.inTx, item.calls)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestHooks_AfterFind(t *testing.T) {
+	repo, mock := setupClientes(t)
+	ctx := t.Context()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email FROM "public"."clientes"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "a@x.com").AddRow(2, "b@x.com"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, email FROM "public"."clientes" WHERE id = $1 LIMIT 1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, "a@x.com"))
+
+	items, err := repo.NewQuery(ctx).Query()
+	if err != nil {
+		t.Fatalf("Query falhou: %s", err)
+	}
+	first, err := repo.Where(ctx, "id = ?", 1).First()
+	if err != nil {
+		t.Fatalf("First falhou: %s", err)
+	}
+
+	for _, item := range append(items, first) {
+		if strings.Join(item.calls, ",") != "AfterFind" {
+			t.Errorf("Esperava AfterFind, obteve %v", item.calls)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maskedValue substitui os argumentos de colunas sensíveis nos logs.
+const maskedValue = "***"
+
+// maxArgLen é o tamanho a partir do qual textos são cortados nos logs.
+const maxArgLen = 120
+
+// sensitiveColumn reconhece colunas cujo valor não deve aparecer nos logs.
+var sensitiveColumn = regexp.MustCompile(`(?i)senha|password|passwd|secret|segredo|token|hash|api_?key|cpf|cartao|card`)
+
+var (
+	// comparação ou atribuição: col = $1, "col" LIKE ?2, t.col <> $3
+	comparedParam = regexp.MustCompile(`(?i)"?(\w+)"?\s*(?:=|<>|!=|<=|>=|<|>|\bI?LIKE\b)\s*([$?]\d+)`)
+	// lista do IN: col IN ($1, $2)
+	inParams = regexp.MustCompile(`(?i)"?(\w+)"?\s+(?:NOT\s+)?IN\s*\(([^()]*)\)`)
+	// colunas do INSERT: INSERT INTO t (a, b) VALUES
+	insertColumns = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^()]*)\)\s*VALUES\s*`)
+	// uma linha do VALUES: ($1, DEFAULT, $2)
+	valuesRow = regexp.MustCompile(`^\s*\(([^()]*)\)\s*,?`)
+	// parâmetro numerado: $1 ou ?1
+	numberedParam = regexp.MustCompile(`^[$?](\d+)$`)
+)
+
+/*
+MaskArgs prepara os argumentos de query para log: valores ligados a
+colunas sensíveis (senha, token, hash...) viram "***", []byte vira
+"<N bytes>" e textos longos são cortados. O slice original não é alterado.
+
+As colunas são identificadas pelo SQL gerado pelo repositório
+("col = $1", "col IN ($1, $2)" e a lista de colunas do INSERT);
+parâmetros em expressões livres não são reconhecidos.
+*/
+func MaskArgs(query string, args []any) []any {
+	if len(args) == 0 {
+		return nil
+	}
+	sensitive := sensitiveParams(query)
+
+	masked := make([]any, len(args))
+	for i, arg := range args {
+		if sensitive[i+1] {
+			masked[i] = maskedValue
+			continue
+		}
+		masked[i] = summarizeArg(arg)
+	}
+	return masked
+}
+
+// sensitiveParams devolve os números (1-based) dos parâmetros ligados a colunas sensíveis.
+func sensitiveParams(query string) map[int]bool {
+	sensitive := make(map[int]bool)
+	mark := func(col, param string) {
+		if !sensitiveColumn.MatchString(col) {
+			return
+		}
+		if m := numberedParam.FindStringSubmatch(strings.TrimSpace(param)); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			sensitive[n] = true
+		}
+	}
+
+	for _, m := range comparedParam.FindAllStringSubmatch(query, -1) {
+		mark(m[1], m[2])
+	}
+	for _, m := range inParams.FindAllStringSubmatch(query, -1) {
+		for _, param := range strings.Split(m[2], ",") {
+			mark(m[1], param)
+		}
+	}
+
+	// INSERT: cada célula do VALUES corresponde à coluna na mesma posição
+	if loc := insertColumns.FindStringSubmatchIndex(query); loc != nil {
+		cols := strings.Split(query[loc[2]:loc[3]], ",")
+		rest := query[loc[1]:]
+		for {
+			row := valuesRow.FindStringSubmatchIndex(rest)
+			if row == nil {
+				break
+			}
+			for i, cell := range strings.Split(rest[row[2]:row[3]], ",") {
+				if i < len(cols) {
+					mark(strings.Trim(strings.TrimSpace(cols[i]), `"`), cell)
+				}
+			}
+			rest = rest[row[1]:]
+		}
+	}
+	return sensitive
+}
+
+// summarizeArg resume valores grandes para caberem numa linha de log.
+func summarizeArg(arg any) any {
+	switch v := arg.(type) {
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(v))
+	case string:
+		if utf8.RuneCountInString(v) > maxArgLen {
+			return string([]rune(v)[:maxArgLen]) + "…"
+		}
+	}
+	return arg
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"deskapp/src/apps/core/model/entities"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// entityMeta guarda as informações de mapeamento de uma entidade,
+// calculadas uma única vez por tipo.
+type entityMeta struct {
+	columns       []string
+	primaryKeys   []string
+	fields        map[string][]int // coluna -> índice do campo na struct
+	softDelete    bool             // possui deleted_at (ver softdelete.go)
+	versionColumn string           // coluna de versão para lock otimista ("" se não houver)
+	readonly      map[string]bool  // db:",readonly": nunca gravadas
+	omitInsert    map[string]bool  // db:",omitinsert": fora do INSERT
+}
+
+var metaCache sync.Map // reflect.Type -> *entityMeta
+
+// metaOf retorna (e cacheia) os metadados da entidade.
+func metaOf(entity entities.Entity) *entityMeta {
+	t := reflect.TypeOf(entity)
+	if cached, ok := metaCache.Load(t); ok {
+		return cached.(*entityMeta)
+	}
+
+	m := &entityMeta{columns: entity.Columns()}
+	m.readonly, m.omitInsert = writeOptions(entity)
+	m.primaryKeys = resolvePrimaryKeys(entity, m.columns)
+	m.softDelete = m.hasColumn(ColumnDeletedAt)
+	m.versionColumn = resolveVersionColumn(entity, m)
+	if st := t.Elem(); st.Kind() == reflect.Struct {
+		m.fields = structFieldsByColumn(st)
+	}
+
+	actual, _ := metaCache.LoadOrStore(t, m)
+	return actual.(*entityMeta)
+}
+
+// resolvePrimaryKeys descobre a PK na ordem: método PrimaryKey(),
+// tag `db:",pk"` (ou `pk:"true"`) nos campos e, por último, a coluna "id".
+func resolvePrimaryKeys(entity entities.Entity, columns []string) []string {
+	if pker, ok := entity.(entities.PrimaryKeyer); ok {
+		return pker.PrimaryKey()
+	}
+
+	var keys []string
+	t := reflect.TypeOf(entity)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if tag := parseColumnTag(t.Field(i)); tag.pk && tag.isColumn() {
+				keys = append(keys, tag.name)
+			}
+		}
+	}
+	if len(keys) > 0 {
+		return keys
+	}
+
+	if slices.Contains(columns, "id") {
+		return []string{"id"}
+	}
+	return nil
+}
+
+// writeOptions lê as opções readonly e omitinsert das tags `db` da entidade.
+func writeOptions(entity entities.Entity) (readonly, omitInsert map[string]bool) {
+	readonly, omitInsert = make(map[string]bool), make(map[string]bool)
+	t := reflect.TypeOf(entity).Elem()
+	if t.Kind() != reflect.Struct {
+		return readonly, omitInsert
+	}
+	for i := 0; i < t.NumField(); i++ {
+		tag := parseColumnTag(t.Field(i))
+		if !tag.isColumn() {
+			continue
+		}
+		readonly[tag.name] = tag.readonly
+		omitInsert[tag.name] = tag.omitInsert
+	}
+	return readonly, omitInsert
+}
+
+// skipOnInsert informa se a coluna fica fora do INSERT (readonly ou omitinsert).
+func (m *entityMeta) skipOnInsert(col string) bool {
+	return m.readonly[col] || m.omitInsert[col]
+}
+
+// hasColumn informa se a coluna faz parte de Columns().
+func (m *entityMeta) hasColumn(col string) bool {
+	return slices.Contains(m.columns, col)
+}
+
+// field devolve o campo da struct mapeado para a coluna, se ela existir em Columns().
+func (m *entityMeta) field(entity entities.Entity, col string) (reflect.Value, bool) {
+	index, ok := m.fields[col]
+	if !ok || !m.hasColumn(col) {
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(entity).Elem().FieldByIndex(index), true
+}
+
+// isPrimaryKey informa se a coluna faz parte da PK.
+func (m *entityMeta) isPrimaryKey(col string) bool {
+	return slices.Contains(m.primaryKeys, col)
+}
+
+// isGeneratedKey informa se a coluna é uma PK simples, candidata a ser
+// gerada pelo banco (serial, identity, uuid default) quando vier zerada.
+func (m *entityMeta) isGeneratedKey(col string) bool {
+	return len(m.primaryKeys) == 1 && m.primaryKeys[0] == col
+}
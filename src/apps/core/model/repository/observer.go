@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// QueryEvent descreve um comando executado pelo repositório.
+type QueryEvent struct {
+	SQL      string
+	Args     []any // já mascarados (ver MaskArgs)
+	Start    time.Time
+	Duration time.Duration // em consultas, inclui a leitura das linhas
+	Rows     int64         // linhas lidas ou afetadas; -1 quando o driver não informa
+	Err      error
+	Slow     bool // Duration passou de SetSlowQueryThreshold
+}
+
+/*
+QueryObserver recebe cada comando executado pelo repositório, depois que
+ele termina. Use SetQueryObserver para todos os repositórios ou
+UseObserver para um só:
+
+	repository.SetQueryObserver(repository.MultiObserver(
+		repository.NewLogObserver(logger, false),
+		recorder,
+	))
+*/
+type QueryObserver interface {
+	ObserveQuery(ctx context.Context, ev QueryEvent)
+}
+
+// QueryObserverFunc adapta uma função a QueryObserver.
+type QueryObserverFunc func(ctx context.Context, ev QueryEvent)
+
+func (f QueryObserverFunc) ObserveQuery(ctx context.Context, ev QueryEvent) { f(ctx, ev) }
+
+// MultiObserver repassa cada evento para todos os observers, na ordem.
+func MultiObserver(observers ...QueryObserver) QueryObserver {
+	return QueryObserverFunc(func(ctx context.Context, ev QueryEvent) {
+		for _, o := range observers {
+			if o != nil {
+				o.ObserveQuery(ctx, ev)
+			}
+		}
+	})
+}
+
+var (
+	observerMu    sync.RWMutex
+	queryObserver QueryObserver
+	slowThreshold time.Duration
+)
+
+// SetQueryObserver define o observer padrão dos repositórios (nil desliga).
+func SetQueryObserver(o QueryObserver) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+	queryObserver = o
+}
+
+// SetSlowQueryThreshold define a partir de quanto tempo um comando é
+// marcado como lento (QueryEvent.Slow). Zero desliga a marcação.
+func SetSlowQueryThreshold(d time.Duration) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+	slowThreshold = d
+}
+
+// UseObserver substitui o observer padrão apenas neste repositório.
+func (r *BaseRepository[T, P]) UseObserver(o QueryObserver) *BaseRepository[T, P] {
+	r.observer = o
+	return r
+}
+
+// observe monta o evento e o entrega ao observer do repositório (ou ao padrão).
+func (r *BaseRepository[T, P]) observe(ctx context.Context, start time.Time, query string, args []any, rows int64, err error) {
+	observerMu.RLock()
+	o, threshold := queryObserver, slowThreshold
+	observerMu.RUnlock()
+	if r.observer != nil {
+		o = r.observer
+	}
+	if o == nil {
+		return
+	}
+
+	elapsed := time.Since(start)
+	o.ObserveQuery(ctx, QueryEvent{
+		SQL:      query,
+		Args:     MaskArgs(query, args),
+		Start:    start,
+		Duration: elapsed,
+		Rows:     rows,
+		Err:      err,
+		Slow:     threshold > 0 && elapsed >= threshold,
+	})
+}
+
+// exec executa um comando em ex e o registra no observer.
+func (r *BaseRepository[T, P]) exec(ctx context.Context, ex Executor, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := ex.ExecContext(ctx, query, args...)
+	rows := int64(-1)
+	if err == nil {
+		if n, rErr := result.RowsAffected(); rErr == nil {
+			rows = n
+		}
+	}
+	r.observe(ctx, start, query, args, rows, err)
+	return result, err
+}
+
+// query executa uma consulta em ex; o evento é registrado no Close das linhas.
+func (r *BaseRepository[T, P]) query(ctx context.Context, ex Executor, query string, args ...any) (*observedRows, error) {
+	start := time.Now()
+	rows, err := ex.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.observe(ctx, start, query, args, 0, err)
+		return nil, err
+	}
+	return &observedRows{Rows: rows, done: func(n int64, err error) {
+		r.observe(ctx, start, query, args, n, err)
+	}}, nil
+}
+
+// queryRow executa uma consulta de uma linha; o evento é registrado no Scan.
+func (r *BaseRepository[T, P]) queryRow(ctx context.Context, ex Executor, query string, args ...any) *observedRow {
+	start := time.Now()
+	return &observedRow{row: ex.QueryRowContext(ctx, query, args...), done: func(n int64, err error) {
+		r.observe(ctx, start, query, args, n, err)
+	}}
+}
+
+// observedRows conta as linhas lidas e avisa o observer uma única vez, no Close.
+type observedRows struct {
+	*sql.Rows
+	n      int64
+	done   func(rows int64, err error)
+	closed bool
+}
+
+func (o *observedRows) Next() bool {
+	if o.Rows.Next() {
+		o.n++
+		return true
+	}
+	return false
+}
+
+func (o *observedRows) Close() error {
+	err := o.Rows.Close()
+	if !o.closed {
+		o.closed = true
+		o.done(o.n, o.Rows.Err())
+	}
+	return err
+}
+
+// observedRow avisa o observer no Scan, quando o resultado é lido.
+type observedRow struct {
+	row  *sql.Row
+	done func(rows int64, err error)
+}
+
+func (o *observedRow) Scan(dest ...any) error {
+	err := o.row.Scan(dest...)
+	switch err {
+	case nil:
+		o.done(1, nil)
+	case sql.ErrNoRows:
+		o.done(0, nil)
+	default:
+		o.done(0, err)
+	}
+	return err
+}
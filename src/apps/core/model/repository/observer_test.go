@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// collect devolve um observer que acumula os eventos recebidos.
+func collect(events *[]QueryEvent) QueryObserver {
+	return QueryObserverFunc(func(_ context.Context, ev QueryEvent) {
+		*events = append(*events, ev)
+	})
+}
+
+func TestObserver_ReportsRowsAndDuration(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+	var events []QueryEvent
+	repo.UseObserver(collect(&events))
+
+	cols := []string{"id", "name", "age"}
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."users"`)).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "Ana", 20))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" WHERE age > $1`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "Ana", 20).AddRow(2, "Bia", 30))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."users"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user := &MockUser{Name: sql.NullString{String: "Ana", Valid: true}, Age: 20}
+	if err := repo.Insert(ctx, user); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if _, err := repo.Where(ctx, "age > ?", 18).Query(); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if err := repo.Delete(ctx, user); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Esperava 3 eventos, obteve %d: %+v", len(events), events)
+	}
+	for i, want := range []int64{1, 2, 1} {
+		if events[i].Rows != want || events[i].Err != nil || events[i].Duration <= 0 {
+			t.Errorf("evento %d: rows=%d err=%v duração=%s", i, events[i].Rows, events[i].Err, events[i].Duration)
+		}
+	}
+	if !reflect.DeepEqual(events[1].Args, []any{18}) {
+		t.Errorf("Argumentos inesperados: %v", events[1].Args)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestObserver_SlowAndErrors(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+	var events []QueryEvent
+	repo.UseObserver(collect(&events))
+	SetSlowQueryThreshold(5 * time.Millisecond)
+	t.Cleanup(func() { SetSlowQueryThreshold(0) })
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*)`)).
+		WillDelayFor(10 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age`)).
+		WillReturnError(sql.ErrConnDone)
+
+	repo.NewQuery(ctx).Count()
+	repo.NewQuery(ctx).First()
+	repo.NewQuery(ctx).First()
+
+	if len(events) != 3 {
+		t.Fatalf("Esperava 3 eventos, obteve %d", len(events))
+	}
+	if !events[0].Slow || events[1].Slow {
+		t.Errorf("Só o COUNT deveria ser lento: %v %v", events[0].Slow, events[1].Slow)
+	}
+	if events[1].Rows != 0 || events[1].Err != nil {
+		t.Errorf("Nenhuma linha não é erro: rows=%d err=%v", events[1].Rows, events[1].Err)
+	}
+	if events[2].Err == nil {
+		t.Error("Esperava o erro do driver no evento")
+	}
+}
+
+func TestMaskArgs(t *testing.T) {
+	query := `INSERT INTO "public"."usuarios" (nome, senha_hash, foto) VALUES ($1, $2, $3), ($4, DEFAULT, $5)`
+	long := string(make([]rune, maxArgLen+10))
+	got := MaskArgs(query, []any{"Ana", "x1", []byte("png"), long, []byte{}})
+	want := []any{"Ana", maskedValue, "<3 bytes>", string(make([]rune, maxArgLen)) + "…", "<0 bytes>"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("INSERT: esperava %q, obteve %q", want, got)
+	}
+
+	query = `UPDATE "usuarios" SET nome = $1, "token" = $2 WHERE id = $3 AND api_key IN ($4, $5)`
+	got = MaskArgs(query, []any{"Ana", "t", 7, "k1", "k2"})
+	want = []any{"Ana", maskedValue, 7, maskedValue, maskedValue}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UPDATE: esperava %v, obteve %v", want, got)
+	}
+}
+
+func TestQueryRecorder(t *testing.T) {
+	rec := NewQueryRecorder(2, 1)
+
+	ctx, req := rec.Begin(context.Background(), "GET /usuarios")
+	for i := range 3 {
+		rec.ObserveQuery(ctx, QueryEvent{SQL: "q" + string(rune('0'+i)), Slow: i == 0})
+	}
+	rec.ObserveQuery(context.Background(), QueryEvent{SQL: "fora da requisição"})
+	rec.End(req)
+
+	_, empty := rec.Begin(context.Background(), "GET /")
+	rec.End(empty) // sem consultas, não entra na lista
+
+	recent := rec.Recent()
+	if len(recent) != 1 || recent[0].Label != "GET /usuarios" {
+		t.Fatalf("Esperava só a requisição com consultas, obteve %+v", recent)
+	}
+	r := recent[0]
+	if r.Total != 3 || r.Slow != 1 || len(r.Queries) != 2 || r.Queries[0].SQL != "q1" {
+		t.Errorf("Esperava as 2 últimas de 3 consultas, obteve %+v", r)
+	}
+}
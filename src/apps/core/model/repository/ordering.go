@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// orderTerm é um item do ORDER BY já validado.
+type orderTerm struct {
+	expr  string
+	desc  bool
+	nulls string // "", "FIRST" ou "LAST"
+}
+
+func (o orderTerm) String() string {
+	sql := o.expr
+	if o.desc {
+		sql += " DESC"
+	}
+	if o.nulls != "" {
+		sql += " NULLS " + o.nulls
+	}
+	return sql
+}
+
+// OrderAsc acrescenta "col ASC" à ordenação. A coluna precisa existir em Columns().
+func (qb *QueryBuilder[T, P]) OrderAsc(col string) IQueryBuilder[T, P] {
+	return qb.addOrder(col, false)
+}
+
+// OrderDesc acrescenta "col DESC" à ordenação. A coluna precisa existir em Columns().
+func (qb *QueryBuilder[T, P]) OrderDesc(col string) IQueryBuilder[T, P] {
+	return qb.addOrder(col, true)
+}
+
+// NullsFirst aplica NULLS FIRST à última ordenação adicionada.
+func (qb *QueryBuilder[T, P]) NullsFirst() IQueryBuilder[T, P] {
+	return qb.setNulls("FIRST")
+}
+
+// NullsLast aplica NULLS LAST à última ordenação adicionada.
+func (qb *QueryBuilder[T, P]) NullsLast() IQueryBuilder[T, P] {
+	return qb.setNulls("LAST")
+}
+
+/*
+OrderBy substitui a ordenação a partir de uma lista no formato SQL, validando
+cada item contra as colunas da entidade:
+
+	"name DESC, created_at ASC NULLS LAST"
+
+Qualquer item que não seja "coluna [ASC|DESC] [NULLS FIRST|LAST]" com uma
+coluna conhecida faz a consulta falhar, então é seguro repassar valores da
+requisição. Para expressões confiáveis (ex: aliases de agregados) use OrderByRaw.
+*/
+func (qb *QueryBuilder[T, P]) OrderBy(orderBy string) IQueryBuilder[T, P] {
+	qb.orders = nil
+	for _, item := range strings.Split(orderBy, ",") {
+		tokens := strings.Fields(item)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		col, rest := tokens[0], strings.Fields(strings.ToUpper(strings.Join(tokens[1:], " ")))
+		desc := false
+		if len(rest) > 0 && (rest[0] == "ASC" || rest[0] == "DESC") {
+			desc = rest[0] == "DESC"
+			rest = rest[1:]
+		}
+		nulls := ""
+		if len(rest) == 2 && rest[0] == "NULLS" && (rest[1] == "FIRST" || rest[1] == "LAST") {
+			nulls = rest[1]
+			rest = nil
+		}
+		if len(rest) > 0 {
+			qb.fail(fmt.Errorf("ordenação inválida: %q", strings.TrimSpace(item)))
+			continue
+		}
+
+		qb.appendOrder(col, desc, nulls)
+	}
+	return qb
+}
+
+// OrderByRaw acrescenta uma expressão de ordenação sem validação.
+// CUIDADO: nunca repasse valores vindos do usuário.
+func (qb *QueryBuilder[T, P]) OrderByRaw(expr string) IQueryBuilder[T, P] {
+	qb.orders = append(qb.orders, orderTerm{expr: expr})
+	return qb
+}
+
+// Select restringe as colunas do SELECT (projeção parcial). As colunas
+// precisam existir em Columns(); os campos não selecionados ficam zerados.
+func (qb *QueryBuilder[T, P]) Select(cols ...string) IQueryBuilder[T, P] {
+	for _, col := range cols {
+		if !qb.isColumn(col) {
+			qb.fail(fmt.Errorf("coluna inválida no select: %q", col))
+			return qb
+		}
+	}
+	qb.columns = cols
+	return qb
+}
+
+func (qb *QueryBuilder[T, P]) addOrder(col string, desc bool) IQueryBuilder[T, P] {
+	qb.appendOrder(col, desc, "")
+	return qb
+}
+
+func (qb *QueryBuilder[T, P]) appendOrder(col string, desc bool, nulls string) {
+	if !qb.isColumn(col) {
+		qb.fail(fmt.Errorf("coluna de ordenação inválida: %q", col))
+		return
+	}
+	qb.orders = append(qb.orders, orderTerm{expr: col, desc: desc, nulls: nulls})
+}
+
+func (qb *QueryBuilder[T, P]) setNulls(nulls string) IQueryBuilder[T, P] {
+	if len(qb.orders) == 0 {
+		qb.fail(fmt.Errorf("NULLS %s sem ordenação anterior", nulls))
+		return qb
+	}
+	qb.orders[len(qb.orders)-1].nulls = nulls
+	return qb
+}
+
+// isColumn informa se col é uma das colunas da entidade.
+func (qb *QueryBuilder[T, P]) isColumn(col string) bool {
+	var model T
+	return slices.Contains(metaOf(P(&model)).columns, col)
+}
+
+// fail registra o primeiro erro de montagem; ele é devolvido na execução.
+func (qb *QueryBuilder[T, P]) fail(err error) {
+	if qb.err == nil {
+		qb.err = err
+	}
+}
+
+// buildOrderBy junta os itens de ordenação ("" quando não houver).
+func (qb *QueryBuilder[T, P]) buildOrderBy() string {
+	parts := make([]string, len(qb.orders))
+	for i, o := range qb.orders {
+		parts[i] = o.String()
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestQueryBuilder_TypedOrdering(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	expectedSQL := `SELECT id, name, age FROM "public"."users" ORDER BY age DESC NULLS LAST, name`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}))
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}))
+
+	if _, err := repo.NewQuery(ctx).OrderDesc("age").NullsLast().OrderAsc("name").Query(); err != nil {
+		t.Errorf("Query falhou: %s", err)
+	}
+
+	// O mesmo resultado a partir de uma string SQL validada
+	if _, err := repo.NewQuery(ctx).OrderBy("age desc nulls last, name asc").Query(); err != nil {
+		t.Errorf("Query falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_OrderBy_RejectsInjection(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	inputs := []string{
+		"name; DROP TABLE users",
+		"senha",
+		"(SELECT 1)",
+		"name DESC, age RANDOM",
+	}
+	for _, input := range inputs {
+		if _, err := repo.NewQuery(ctx).OrderBy(input).Query(); err == nil {
+			t.Errorf("Esperava erro para a ordenação %q", input)
+		}
+	}
+	if _, err := repo.NewQuery(ctx).OrderDesc("1; --").Query(); err == nil {
+		t.Error("Esperava erro para coluna desconhecida em OrderDesc")
+	}
+
+	// Nenhuma consulta deve chegar ao banco
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_Select(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name FROM "public"."users" WHERE id = $1 LIMIT 1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(5, "Eva"))
+
+	user, err := repo.Where(ctx, "id = ?", 5).Select("id", "name").First()
+	if err != nil {
+		t.Fatalf("First falhou: %s", err)
+	}
+	if user.ID != 5 || user.Name.String != "Eva" || user.Age != 0 {
+		t.Errorf("Resultado inesperado: %+v", user)
+	}
+
+	if _, err := repo.NewQuery(ctx).Select("id", "password").Query(); err == nil {
+		t.Error("Esperava erro para coluna desconhecida no Select")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"bytes"
+	"deskapp/src/internal/utils"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefaultPerPage é usado quando Paginate recebe perPage = 0.
+const DefaultPerPage uint64 = 20
+
+// Page é o resultado de uma consulta paginada.
+type Page[T any] struct {
+	Items      []*T   `json:"items"`
+	Total      int64  `json:"total"`
+	Page       uint64 `json:"page"`
+	PerPage    uint64 `json:"per_page"`
+	Pages      uint64 `json:"pages"`
+	HasNext    bool   `json:"has_next"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// After ativa a paginação por keyset (cursor): em vez de OFFSET, busca as
+// linhas com PK maior que a do cursor, ordenadas pela PK. Um cursor vazio
+// traz a primeira página. O próximo cursor vem em Page.NextCursor.
+func (qb *QueryBuilder[T, P]) After(cursor string) IQueryBuilder[T, P] {
+	qb.keyset = true
+	if cursor == "" {
+		return qb
+	}
+
+	var model T
+	values, err := decodeCursor(cursor, cursorTypes(reflect.TypeOf(model), metaOf(P(&model))))
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	qb.cursor = values
+	return qb
+}
+
+/*
+Paginate executa a consulta paginada.
+
+  - modo offset (padrão): faz um COUNT com as mesmas condições e aplica LIMIT/OFFSET
+  - modo keyset (após After): não conta o total; HasNext e NextCursor indicam a continuação
+*/
+func (qb *QueryBuilder[T, P]) Paginate(page, perPage uint64) (*Page[T], error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = DefaultPerPage
+	}
+
+	if qb.keyset {
+		return qb.paginateKeyset(page, perPage)
+	}
+
+	total, err := qb.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Page[T]{
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+		Pages:   (uint64(total) + perPage - 1) / perPage,
+		Items:   []*T{},
+	}
+	result.HasNext = page < result.Pages
+
+	// Página além do fim: evita uma consulta que certamente volta vazia
+	if total == 0 || page > result.Pages {
+		return result, nil
+	}
+
+	pageQuery := qb.clone()
+	pageQuery.limit = perPage
+	pageQuery.offset = (page - 1) * perPage
+	items, err := pageQuery.Query()
+	if err != nil {
+		return nil, err
+	}
+	if items != nil {
+		result.Items = items
+	}
+	return result, nil
+}
+
+// PaginateWith aplica a ordenação, o cursor e a página lidos da requisição
+// (ver utils.ReadPagination) e executa Paginate. No modo keyset a ordenação
+// é sempre pela PK, então o sort é ignorado.
+func (qb *QueryBuilder[T, P]) PaginateWith(p utils.Pagination) (*Page[T], error) {
+	if !p.Keyset {
+		// Colunas fora de Columns() fazem a consulta falhar (sem injeção de SQL)
+		for _, field := range p.Sort {
+			if field.Desc {
+				qb.OrderDesc(field.Column)
+			} else {
+				qb.OrderAsc(field.Column)
+			}
+		}
+	}
+	if p.Keyset {
+		qb.After(p.Cursor)
+	}
+	return qb.Paginate(p.Page, p.PerPage)
+}
+
+// paginateKeyset busca perPage+1 linhas a partir do cursor para saber se há
+// próxima página. A condição do cursor vai numa cópia do builder, que pode
+// ser reaproveitado (ex: Count seguido da próxima página).
+func (qb *QueryBuilder[T, P]) paginateKeyset(page, perPage uint64) (*Page[T], error) {
+	var model T
+	pks := metaOf(P(&model)).primaryKeys
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("paginação por cursor exige chave primária")
+	}
+
+	pageQuery := qb.clone()
+	if qb.cursor != nil {
+		if len(qb.cursor) != len(pks) {
+			return nil, fmt.Errorf("cursor inválido para a chave %v", pks)
+		}
+		pageQuery.AndCond(Expr(
+			fmt.Sprintf("(%s) > (%s)", strings.Join(pks, ", "), placeholderList(len(pks))),
+			qb.cursor...,
+		))
+	}
+	pageQuery.orders = nil
+	for _, pk := range pks {
+		pageQuery.orders = append(pageQuery.orders, orderTerm{expr: pk})
+	}
+	pageQuery.limit = perPage + 1
+	pageQuery.offset = 0
+
+	items, err := pageQuery.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Page[T]{Page: page, PerPage: perPage, Items: []*T{}}
+	if uint64(len(items)) > perPage {
+		result.HasNext = true
+		items = items[:perPage]
+	}
+	if len(items) > 0 {
+		result.Items = items
+	}
+
+	if result.HasNext {
+		colsMap, err := qb.repo.getEntityColumnMap(P(items[len(items)-1]))
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, len(pks))
+		for i, pk := range pks {
+			values[i] = colsMap[pk]
+		}
+		if result.NextCursor, err = encodeCursor(values); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// encodeCursor serializa os valores da PK em um token opaco e seguro para URL.
+func encodeCursor(values []any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// cursorTypes devolve o tipo Go de cada coluna da PK na struct st (nil se a
+// coluna não tiver campo).
+func cursorTypes(st reflect.Type, meta *entityMeta) []reflect.Type {
+	types := make([]reflect.Type, len(meta.primaryKeys))
+	for i, pk := range meta.primaryKeys {
+		if index, ok := meta.fields[pk]; ok {
+			types[i] = st.FieldByIndex(index).Type
+		}
+	}
+	return types
+}
+
+/*
+decodeCursor é o inverso de encodeCursor. Cada valor é lido no tipo do
+campo da PK (types), para que id > ? compare números com números também
+no SQLite. Sem o tipo, números voltam como texto para não perder precisão
+em chaves bigint; o Postgres converte pelo tipo da coluna.
+*/
+func decodeCursor(cursor string, types []reflect.Type) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cursor inválido: %w", err)
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("cursor inválido: %w", err)
+	}
+	values := make([]any, len(raw))
+	for i, item := range raw {
+		if i < len(types) && types[i] != nil {
+			v := reflect.New(types[i])
+			if err := json.Unmarshal(item, v.Interface()); err != nil {
+				return nil, fmt.Errorf("cursor inválido: %w", err)
+			}
+			values[i] = v.Elem().Interface()
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(item))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values[i]); err != nil {
+			return nil, fmt.Errorf("cursor inválido: %w", err)
+		}
+		if n, ok := values[i].(json.Number); ok {
+			values[i] = n.String()
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestQueryBuilder_Paginate(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "public"."users" WHERE age > $1`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" WHERE age > $1 ORDER BY name LIMIT 2 OFFSET 2`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(3, "Carla", 20).AddRow(4, "Davi", 22))
+
+	page, err := repo.Where(ctx, "age > ?", 18).OrderBy("name").Paginate(2, 2)
+	if err != nil {
+		t.Fatalf("Paginate falhou: %s", err)
+	}
+
+	if page.Total != 5 || page.Pages != 3 || !page.HasNext || len(page.Items) != 2 {
+		t.Errorf("Página inesperada: %+v", page)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_Paginate_Keyset(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	// Primeira página: sem condição de cursor, busca perPage+1 linhas
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" ORDER BY id LIMIT 3`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).
+			AddRow(1, "Alice", 30).AddRow(2, "Bob", 40).AddRow(3, "Carla", 20))
+
+	first, err := repo.NewQuery(ctx).After("").Paginate(1, 2)
+	if err != nil {
+		t.Fatalf("Paginate (keyset) falhou: %s", err)
+	}
+	if !first.HasNext || len(first.Items) != 2 || first.NextCursor == "" {
+		t.Fatalf("Primeira página inesperada: %+v", first)
+	}
+
+	// Segunda página continua a partir da última PK devolvida
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" WHERE (id) > ($1) ORDER BY id LIMIT 3`)).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(3, "Carla", 20))
+
+	second, err := repo.NewQuery(ctx).After(first.NextCursor).Paginate(2, 2)
+	if err != nil {
+		t.Fatalf("Paginate (keyset) falhou: %s", err)
+	}
+	if second.HasNext || len(second.Items) != 1 || second.NextCursor != "" {
+		t.Errorf("Segunda página inesperada: %+v", second)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_Paginate_ReusedBuilder(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	cursor, err := encodeCursor([]any{int64(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A página não altera o builder: Count e a mesma página de novo não
+	// herdam a condição do cursor, a ordenação nem o LIMIT
+	pageSQL := `SELECT id, name, age FROM "public"."users" WHERE age > $1 AND (id) > ($2) ORDER BY id LIMIT 3`
+	mock.ExpectQuery(regexp.QuoteMeta(pageSQL)).
+		WithArgs(18, int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(3, "Carla", 20))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "public"."users" WHERE age > $1`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(pageSQL)).
+		WithArgs(18, int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(3, "Carla", 20))
+
+	qb := repo.Where(ctx, "age > ?", 18).After(cursor)
+	if _, err := qb.Paginate(1, 2); err != nil {
+		t.Fatalf("Paginate falhou: %s", err)
+	}
+	if total, err := qb.Count(); err != nil || total != 3 {
+		t.Errorf("Count inesperado: %d, %v", total, err)
+	}
+	if _, err := qb.Paginate(1, 2); err != nil {
+		t.Fatalf("Paginate repetido falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestSQLite_Paginate_Keyset(t *testing.T) {
+	repo := setupSQLite(t)
+	ctx := t.Context()
+	for i := 1; i <= 12; i++ {
+		if err := repo.Insert(ctx, &MockUser{Age: i}); err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+	}
+
+	// Com o id como texto, "10" < "9" e a terceira página sairia errada
+	var ids []int64
+	cursor := ""
+	for range 3 {
+		page, err := repo.NewQuery(ctx).After(cursor).Paginate(1, 5)
+		if err != nil {
+			t.Fatalf("Paginate: %v", err)
+		}
+		for _, u := range page.Items {
+			ids = append(ids, u.ID)
+		}
+		cursor = page.NextCursor
+	}
+	if len(ids) != 12 || ids[0] != 1 || ids[11] != 12 || cursor != "" {
+		t.Errorf("Esperava os ids 1..12 em três páginas, obteve %v (cursor %q)", ids, cursor)
+	}
+}
+
+func TestDecodeCursor_Types(t *testing.T) {
+	cursor, _ := encodeCursor([]any{int64(9007199254740993), "A-1"})
+	values, err := decodeCursor(cursor, []reflect.Type{reflect.TypeFor[int64](), nil})
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if values[0] != int64(9007199254740993) || values[1] != "A-1" {
+		t.Errorf("Valores inesperados: %#v", values)
+	}
+	if _, err := decodeCursor(cursor, []reflect.Type{reflect.TypeFor[int64](), reflect.TypeFor[int64]()}); err == nil {
+		t.Error("Esperava erro para texto numa PK numérica")
+	}
+}
+
+func TestQueryBuilder_After_InvalidCursor(t *testing.T) {
+	repo, _, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	if _, err := repo.NewQuery(ctx).After("%%%").Paginate(1, 10); err == nil {
+		t.Error("Esperava erro para cursor inválido")
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"deskapp/src/apps/core/model/entities"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+Preload carrega as relações informadas (ver entities.Relational) depois da
+consulta principal, com uma consulta por relação usando IN nas chaves dos
+registros já lidos — evita o N+1 de buscar os filhos registro a registro.
+
+	usuarios, err := repo.NewQuery(ctx).Preload("Pedidos").Query()
+*/
+func (qb *QueryBuilder[T, P]) Preload(relations ...string) IQueryBuilder[T, P] {
+	qb.preload = append(qb.preload, relations...)
+	return qb
+}
+
+// loadRelations executa o Preload para os registros já escaneados.
+// ctx é passado à parte para que o streaming com cursor use a própria transação.
+func (qb *QueryBuilder[T, P]) loadRelations(ctx context.Context, items []*T) error {
+	if len(qb.preload) == 0 || len(items) == 0 {
+		return nil
+	}
+
+	var model T
+	relational, ok := any(P(&model)).(entities.Relational)
+	if !ok {
+		return fmt.Errorf("preload: %T não declara relações", P(&model))
+	}
+	declared := make(map[string]entities.Relation)
+	for _, rel := range relational.Relations() {
+		declared[rel.Name] = rel
+	}
+
+	parents := make([]reflect.Value, len(items))
+	for i, item := range items {
+		parents[i] = reflect.ValueOf(item)
+	}
+
+	for _, name := range qb.preload {
+		rel, ok := declared[name]
+		if !ok {
+			return fmt.Errorf("preload: relação %q não declarada em %T", name, P(&model))
+		}
+		if err := qb.loadRelation(ctx, rel, parents); err != nil {
+			return fmt.Errorf("preload %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// loadRelation busca os registros relacionados e os atribui ao campo rel.Name de cada pai.
+func (qb *QueryBuilder[T, P]) loadRelation(ctx context.Context, rel entities.Relation, parents []reflect.Value) error {
+	field, ok := parents[0].Elem().Type().FieldByName(rel.Name)
+	if !ok {
+		return fmt.Errorf("campo %s não encontrado", rel.Name)
+	}
+
+	// O tipo do filho vem do campo: *Filho ou []*Filho
+	fieldType := field.Type
+	many := fieldType.Kind() == reflect.Slice
+	if many {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Pointer || !fieldType.Implements(reflect.TypeFor[entities.Entity]()) {
+		return fmt.Errorf("campo %s deve ser *Entidade ou []*Entidade", rel.Name)
+	}
+	if many != (rel.Kind == entities.HasMany) {
+		return fmt.Errorf("campo %s não combina com o tipo da relação", rel.Name)
+	}
+	childType := fieldType.Elem()
+
+	// 1. Chaves distintas dos pais
+	parentMeta := metaOf(parents[0].Interface().(entities.Entity))
+	keys := make([]any, 0, len(parents))
+	seen := make(map[string]bool)
+	for _, parent := range parents {
+		value, ok := columnValue(parent, parentMeta, rel.LocalKey)
+		if !ok {
+			return fmt.Errorf("coluna %s não encontrada na entidade", rel.LocalKey)
+		}
+		key, valid := relationKey(value)
+		if !valid || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, value)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// 2. Busca os filhos em lotes de IN
+	childMeta := metaOf(reflect.New(childType).Interface().(entities.Entity))
+	children := make(map[string][]reflect.Value)
+	limit := qb.repo.dialect.MaxParams()
+	for start := 0; start < len(keys); start += limit {
+		batch := keys[start:min(start+limit, len(keys))]
+
+		args := make([]any, 0, len(batch))
+		where := In(rel.ForeignKey, batch...).build(qb.repo.dialect, &args)
+		if childMeta.softDelete {
+			where += " AND " + ColumnDeletedAt + " IS NULL"
+		}
+		query := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE %s",
+			strings.Join(childMeta.columns, ", "),
+			qb.repo.dialect.Table(rel.Schema, rel.Table),
+			where,
+		)
+
+		rows, err := qb.repo.query(ctx, qb.repo.reader(ctx), query, args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			child := reflect.New(childType)
+			if err := child.Interface().(entities.Entity).ScanRow(rows); err != nil {
+				rows.Close()
+				return err
+			}
+			value, _ := columnValue(child, childMeta, rel.ForeignKey)
+			if key, valid := relationKey(value); valid {
+				children[key] = append(children[key], child)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	// 3. Distribui os filhos entre os pais
+	for _, parent := range parents {
+		value, _ := columnValue(parent, parentMeta, rel.LocalKey)
+		key, valid := relationKey(value)
+		if !valid {
+			continue
+		}
+		target := parent.Elem().FieldByIndex(field.Index)
+		found := children[key]
+		if many {
+			slice := reflect.MakeSlice(target.Type(), 0, len(found))
+			target.Set(reflect.Append(slice, found...))
+		} else if len(found) > 0 {
+			target.Set(found[0])
+		}
+	}
+	return nil
+}
+
+// columnValue lê o valor do campo mapeado para col em uma entidade (ponteiro).
+func columnValue(entity reflect.Value, meta *entityMeta, col string) (any, bool) {
+	index, ok := meta.fields[col]
+	if !ok {
+		return nil, false
+	}
+	return entity.Elem().FieldByIndex(index).Interface(), true
+}
+
+// relationKey normaliza o valor de uma chave para comparação entre tabelas
+// (ex: int64 do pai e *int32 do filho). valid é false para valores nulos.
+func relationKey(value any) (key string, valid bool) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "", false
+		}
+		value = v
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", false
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return "", false
+	}
+	return fmt.Sprint(rv.Interface()), true
+}
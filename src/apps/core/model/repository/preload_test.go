@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"deskapp/src/apps/core/model/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// MockAutor possui vários livros
+type MockAutor struct {
+	ID   int64  `json:"id"`
+	Nome string `json:"nome"`
+
+	Livros []*MockLivro `json:"livros,omitempty"`
+}
+
+func (m *MockAutor) Columns() []string { return []string{"id", "nome"} }
+
+func (m *MockAutor) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.Nome)
+}
+
+func (m *MockAutor) Relations() []entities.Relation {
+	return []entities.Relation{
+		{Name: "Livros", Kind: entities.HasMany, Table: "livros", Schema: "public", LocalKey: "id", ForeignKey: "autor_id"},
+	}
+}
+
+// MockLivro pertence a um autor (autor_id pode ser nulo)
+type MockLivro struct {
+	ID      int64  `json:"id"`
+	AutorID *int64 `json:"autor_id"`
+
+	Autor *MockAutor `json:"autor,omitempty"`
+}
+
+func (m *MockLivro) Columns() []string { return []string{"id", "autor_id"} }
+
+func (m *MockLivro) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.AutorID)
+}
+
+func (m *MockLivro) Relations() []entities.Relation {
+	return []entities.Relation{
+		{Name: "Autor", Kind: entities.BelongsTo, Table: "autores", Schema: "public", LocalKey: "autor_id", ForeignKey: "id"},
+	}
+}
+
+func TestPreload_HasMany(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockAutor](db, "autores", "public")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, nome FROM "public"."autores"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nome"}).
+			AddRow(1, "Machado").
+			AddRow(2, "Clarice").
+			AddRow(3, "Sem livros"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, autor_id FROM "public"."livros" WHERE autor_id IN ($1, $2, $3)`)).
+		WithArgs(int64(1), int64(2), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "autor_id"}).
+			AddRow(10, 1).
+			AddRow(11, 2).
+			AddRow(12, 1))
+
+	autores, err := repo.NewQuery(t.Context()).Preload("Livros").Query()
+	if err != nil {
+		t.Fatalf("Query com Preload falhou: %s", err)
+	}
+
+	if len(autores[0].Livros) != 2 || autores[0].Livros[0].ID != 10 || autores[0].Livros[1].ID != 12 {
+		t.Errorf("Livros do autor 1 incorretos: %+v", autores[0].Livros)
+	}
+	if len(autores[1].Livros) != 1 || autores[1].Livros[0].ID != 11 {
+		t.Errorf("Livros do autor 2 incorretos: %+v", autores[1].Livros)
+	}
+	if autores[2].Livros == nil || len(autores[2].Livros) != 0 {
+		t.Errorf("Esperava lista vazia para autor sem livros, obteve %+v", autores[2].Livros)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestPreload_BelongsTo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockLivro](db, "livros", "public")
+
+	// Chaves repetidas e nulas não vão para o IN
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, autor_id FROM "public"."livros"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "autor_id"}).
+			AddRow(10, 1).
+			AddRow(11, 1).
+			AddRow(12, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, nome FROM "public"."autores" WHERE id IN ($1)`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nome"}).AddRow(1, "Machado"))
+
+	livros, err := repo.NewQuery(t.Context()).Preload("Autor").Query()
+	if err != nil {
+		t.Fatalf("Query com Preload falhou: %s", err)
+	}
+
+	if livros[0].Autor == nil || livros[0].Autor != livros[1].Autor || livros[0].Autor.Nome != "Machado" {
+		t.Errorf("Autor não atribuído corretamente: %+v / %+v", livros[0].Autor, livros[1].Autor)
+	}
+	if livros[2].Autor != nil {
+		t.Errorf("Livro sem autor não deveria receber relação: %+v", livros[2].Autor)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestPreload_UnknownRelation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockAutor](db, "autores", "public")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, nome FROM "public"."autores"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nome"}).AddRow(1, "Machado"))
+
+	if _, err := repo.NewQuery(t.Context()).Preload("Editora").Query(); err == nil {
+		t.Error("Esperava erro para relação não declarada")
+	}
+}
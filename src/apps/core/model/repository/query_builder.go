@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"deskapp/src/apps/core/model/entities"
-	"errors"
+	"deskapp/src/internal/utils"
 	"fmt"
+	"iter"
+	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -15,12 +18,48 @@ type DBScanner interface {
 }
 
 type IQueryBuilder[T any, P interface { *T; entities.Entity }] interface {
-	And(queryFragment string, arg any) IQueryBuilder[T, P]
+	And(queryFragment string, args ...any) IQueryBuilder[T, P]
+	Or(queryFragment string, args ...any) IQueryBuilder[T, P]
+	Not(queryFragment string, args ...any) IQueryBuilder[T, P]
+	AndCond(cond Condition) IQueryBuilder[T, P]
+	OrCond(cond Condition) IQueryBuilder[T, P]
+	In(col string, values ...any) IQueryBuilder[T, P]
+	NotIn(col string, values ...any) IQueryBuilder[T, P]
+	IsNull(col string) IQueryBuilder[T, P]
+	NotNull(col string) IQueryBuilder[T, P]
+	Between(col string, from, to any) IQueryBuilder[T, P]
+	Like(col string, pattern any) IQueryBuilder[T, P]
+	ILike(col string, pattern any) IQueryBuilder[T, P]
+	GroupBy(cols ...string) IQueryBuilder[T, P]
+	Having(queryFragment string, args ...any) IQueryBuilder[T, P]
 	OrderBy(orderBy string) IQueryBuilder[T, P]
+	OrderAsc(col string) IQueryBuilder[T, P]
+	OrderDesc(col string) IQueryBuilder[T, P]
+	NullsFirst() IQueryBuilder[T, P]
+	NullsLast() IQueryBuilder[T, P]
+	OrderByRaw(expr string) IQueryBuilder[T, P]
+	Select(cols ...string) IQueryBuilder[T, P]
+	WithTrashed() IQueryBuilder[T, P]
+	OnlyTrashed() IQueryBuilder[T, P]
+	Preload(relations ...string) IQueryBuilder[T, P]
+	Cursor(batchSize uint64) IQueryBuilder[T, P]
 	Limit(limit uint64) IQueryBuilder[T, P]
 	Offset(offset uint64) IQueryBuilder[T, P]
+	After(cursor string) IQueryBuilder[T, P]
 	First() (*T, error)
 	Query() ([]*T, error)
+	Each(fn func(*T) error) error
+	Iter() iter.Seq2[*T, error]
+	Paginate(page, perPage uint64) (*Page[T], error)
+	PaginateWith(p utils.Pagination) (*Page[T], error)
+	Count() (int64, error)
+	Exists() (bool, error)
+	Sum(col string) (float64, error)
+	Avg(col string) (float64, error)
+	Min(col string, dest any) error
+	Max(col string, dest any) error
+	PluckInto(col string, dest any) error
+	ScanInto(dest any, exprs ...string) error
 	PrintQuery()
 }
 
@@ -28,28 +67,133 @@ type QueryBuilder[T any, P interface { *T; entities.Entity }] struct {
 	repo    *BaseRepository[T, P] // Também precisa ser genérico
 	ctx     context.Context
 	columns []string
-	wheres  []string
-	args    []any
-	orderBy string
+	wheres  []whereTerm
+	groupBy []string
+	having  []whereTerm
+	orders  []orderTerm
 	limit   uint64
 	offset  uint64
+	preload []string // relações carregadas após a consulta
+	keyset  bool  // paginação por cursor (After)
+	cursor  []any // valores da PK vindos do cursor
+	batch   uint64 // tamanho do lote do cursor no servidor (Cursor)
+	trashed trashedMode // filtro de exclusão lógica (deleted_at)
+	err     error // erro de montagem, devolvido na execução
 	query string
 }
 
-// And adiciona uma condição "AND" à consulta.
-func (qb *QueryBuilder[T, P]) And(queryFragment string, arg any) IQueryBuilder[T, P] {
-	qb.wheres = append(qb.wheres, queryFragment)
-	qb.args = append(qb.args, arg)
-	return qb
+// clone copia o builder para montar uma consulta derivada (a página do
+// Paginate) sem alterar as condições e a ordenação do original.
+func (qb *QueryBuilder[T, P]) clone() *QueryBuilder[T, P] {
+	c := *qb
+	c.wheres = slices.Clone(qb.wheres)
+	c.orders = slices.Clone(qb.orders)
+	return &c
+}
+
+// whereTerm é uma condição do WHERE junto do conectivo que a liga à anterior.
+type whereTerm struct {
+	conj string // "AND" ou "OR"
+	cond Condition
+}
+
+// And adiciona uma condição "AND" à consulta. Use '?' como placeholder.
+func (qb *QueryBuilder[T, P]) And(queryFragment string, args ...any) IQueryBuilder[T, P] {
+	return qb.AndCond(Expr(queryFragment, args...))
 }
 
+// Or adiciona uma condição "OR" à consulta.
+// Ex: Where(ctx, "a = ?", 1).And("b = ?", 2).Or("c = ?", 3) -> a = $1 AND b = $2 OR c = $3
+// Para controlar a precedência, agrupe com AnyOf/AllOf e use AndCond/OrCond.
+func (qb *QueryBuilder[T, P]) Or(queryFragment string, args ...any) IQueryBuilder[T, P] {
+	return qb.OrCond(Expr(queryFragment, args...))
+}
+
+// Not adiciona "AND NOT (fragmento)" à consulta.
+func (qb *QueryBuilder[T, P]) Not(queryFragment string, args ...any) IQueryBuilder[T, P] {
+	return qb.AndCond(Not(Expr(queryFragment, args...)))
+}
 
-// OrderBy define a cláusula ORDER BY.
-func (qb *QueryBuilder[T, P]) OrderBy(orderBy string) IQueryBuilder[T, P] {
-	qb.orderBy = orderBy
+// AndCond adiciona uma condição (ou grupo) com AND.
+func (qb *QueryBuilder[T, P]) AndCond(cond Condition) IQueryBuilder[T, P] {
+	qb.wheres = append(qb.wheres, whereTerm{conj: "AND", cond: cond})
+	qb.checkPlaceholders()
 	return qb
 }
 
+// OrCond adiciona uma condição (ou grupo) com OR.
+func (qb *QueryBuilder[T, P]) OrCond(cond Condition) IQueryBuilder[T, P] {
+	qb.wheres = append(qb.wheres, whereTerm{conj: "OR", cond: cond})
+	qb.checkPlaceholders()
+	return qb
+}
+
+// checkPlaceholders recusa '?' e $n manuais no mesmo builder: os '?' seriam
+// renumerados a partir de $1 e colidiriam com os $n (ex: "name = ?" seguido
+// de "age > $1" viraria name = $1 AND age > $1).
+func (qb *QueryBuilder[T, P]) checkPlaceholders() {
+	var question, manual bool
+	for _, term := range slices.Concat(qb.wheres, qb.having) {
+		q, m := term.cond.placeholderStyle()
+		question, manual = question || q, manual || m
+	}
+	if question && manual {
+		qb.fail(fmt.Errorf("placeholders '?' e $n misturados na mesma consulta: use só um dos estilos"))
+	}
+}
+
+// In adiciona "AND col IN (...)".
+func (qb *QueryBuilder[T, P]) In(col string, values ...any) IQueryBuilder[T, P] {
+	return qb.AndCond(In(col, values...))
+}
+
+// NotIn adiciona "AND col NOT IN (...)".
+func (qb *QueryBuilder[T, P]) NotIn(col string, values ...any) IQueryBuilder[T, P] {
+	return qb.AndCond(NotIn(col, values...))
+}
+
+// IsNull adiciona "AND col IS NULL".
+func (qb *QueryBuilder[T, P]) IsNull(col string) IQueryBuilder[T, P] {
+	return qb.AndCond(IsNull(col))
+}
+
+// NotNull adiciona "AND col IS NOT NULL".
+func (qb *QueryBuilder[T, P]) NotNull(col string) IQueryBuilder[T, P] {
+	return qb.AndCond(NotNull(col))
+}
+
+// Between adiciona "AND col BETWEEN from AND to".
+func (qb *QueryBuilder[T, P]) Between(col string, from, to any) IQueryBuilder[T, P] {
+	return qb.AndCond(Between(col, from, to))
+}
+
+// Like adiciona "AND col LIKE pattern".
+func (qb *QueryBuilder[T, P]) Like(col string, pattern any) IQueryBuilder[T, P] {
+	return qb.AndCond(Like(col, pattern))
+}
+
+// ILike adiciona "AND col ILIKE pattern" (LIKE no SQLite).
+func (qb *QueryBuilder[T, P]) ILike(col string, pattern any) IQueryBuilder[T, P] {
+	return qb.AndCond(ILike(col, pattern))
+}
+
+// buildTerms junta as condições com seus conectivos, acumulando os argumentos.
+func buildTerms(d Dialect, terms []whereTerm, args *[]any) string {
+	var out strings.Builder
+	for _, term := range terms {
+		part := term.cond.build(d, args)
+		if part == "" {
+			continue
+		}
+		if out.Len() > 0 {
+			out.WriteString(" " + term.conj + " ")
+		}
+		out.WriteString(part)
+	}
+	return out.String()
+}
+
+
 // Limit define o LIMIT.
 func (qb *QueryBuilder[T, P]) Limit(limit uint64) IQueryBuilder[T, P] {
 	qb.limit = limit
@@ -62,55 +206,115 @@ func (qb *QueryBuilder[T, P]) Offset(offset uint64) IQueryBuilder[T, P] {
 	return qb
 }
 
+// GroupBy define a cláusula GROUP BY.
+func (qb *QueryBuilder[T, P]) GroupBy(cols ...string) IQueryBuilder[T, P] {
+	qb.groupBy = append(qb.groupBy, cols...)
+	return qb
+}
+
+// Having adiciona uma condição (AND) à cláusula HAVING. Use '?' como placeholder.
+func (qb *QueryBuilder[T, P]) Having(queryFragment string, args ...any) IQueryBuilder[T, P] {
+	qb.having = append(qb.having, whereTerm{conj: "AND", cond: Expr(queryFragment, args...)})
+	qb.checkPlaceholders()
+	return qb
+}
+
 // buildSelectSQL é um helper interno para montar a string SQL final.
 func (qb *QueryBuilder[T, P]) buildSelectSQL() (string, []any) {
+	if len(qb.columns) > 0 {
+		return qb.buildSQL(strings.Join(qb.columns, ", "), true)
+	}
+	var model T
+	pModel := P(&model)
+	return qb.buildSQL(strings.Join(pModel.Columns(), ", "), true)
+}
+
+// scanEntity escaneia a linha em dest pelo ScanRow da entidade ou, numa
+// projeção parcial (Select), campo a campo pelas colunas escolhidas.
+func (qb *QueryBuilder[T, P]) scanEntity(row DBScanner, dest *T) error {
+	if len(qb.columns) == 0 {
+		return P(dest).ScanRow(row)
+	}
+
+	fields := metaOf(P(dest)).fields
+	v := reflect.ValueOf(dest).Elem()
+	targets := make([]any, len(qb.columns))
+	for i, col := range qb.columns {
+		index, ok := fields[col]
+		if !ok {
+			return fmt.Errorf("coluna %q sem campo correspondente na entidade", col)
+		}
+		targets[i] = v.FieldByIndex(index).Addr().Interface()
+	}
+	return row.Scan(targets...)
+}
+
+// buildSQL monta "SELECT <projection> FROM ..." reaproveitando WHERE, GROUP BY
+// e HAVING do builder. Com paginate=false, ORDER BY, LIMIT e OFFSET são
+// omitidos (usado em contagens e agregados).
+func (qb *QueryBuilder[T, P]) buildSQL(projection string, paginate bool) (string, []any) {
 	var query strings.Builder
-	
+	args := make([]any, 0)
+
 	// 1. SELECT
 	query.WriteString("SELECT ")
-	var model T
-	pModel := P(&model)
-	query.WriteString(strings.Join(pModel.Columns(), ", "))
+	query.WriteString(projection)
 
 	// 2. FROM
 	query.WriteString(" FROM ")
 	query.WriteString(qb.repo.getFullTableName())
 
 	// 3. WHERE
-	if len(qb.wheres) > 0 {
+	if where := qb.buildWhere(&args); where != "" {
 		query.WriteString(" WHERE ")
-		query.WriteString(strings.Join(qb.wheres, " AND "))
+		query.WriteString(where)
 	}
 
-	// 4. ORDER BY
-	if qb.orderBy != "" {
-		query.WriteString(" ORDER BY ")
-		query.WriteString(qb.orderBy) // CUIDADO: ORDER BY não é parametrizável
+	// 4. GROUP BY / HAVING
+	if len(qb.groupBy) > 0 {
+		query.WriteString(" GROUP BY ")
+		query.WriteString(strings.Join(qb.groupBy, ", "))
 	}
-
-	// 5. LIMIT
-	if qb.limit > 0 {
-		query.WriteString(fmt.Sprintf(" LIMIT %d", qb.limit))
+	if having := buildTerms(qb.repo.dialect, qb.having, &args); having != "" {
+		query.WriteString(" HAVING ")
+		query.WriteString(having)
 	}
 
-	// 6. OFFSET
-	if qb.offset > 0 {
-		query.WriteString(fmt.Sprintf(" OFFSET %d", qb.offset))
+	if paginate {
+		// 5. ORDER BY
+		if orderBy := qb.buildOrderBy(); orderBy != "" {
+			query.WriteString(" ORDER BY ")
+			query.WriteString(orderBy) // itens validados em ordering.go
+		}
+
+		// 6. LIMIT / OFFSET
+		query.WriteString(qb.repo.dialect.LimitOffset(qb.limit, qb.offset))
 	}
 
 	qb.query = query.String()
 
-	return query.String(), qb.args
+	return query.String(), args
 }
 
+// PrintQuery registra no log o SELECT que a consulta executaria, com os
+// argumentos mascarados (ver MaskArgs). Para acompanhar os comandos de
+// fato executados, use um QueryObserver.
 func (qb *QueryBuilder[T, P]) PrintQuery() {
-	fmt.Println(qb.query)
+	if qb.err != nil {
+		queryLogger.Warningf("[SQL] consulta inválida: %v", qb.err)
+		return
+	}
+	query, args := qb.buildSelectSQL()
+	queryLogger.Infof("[SQL] %s | args=%v", query, MaskArgs(query, args))
 }
 
 // Query executa a consulta e retorna *sql.Rows (para múltiplos resultados).
 func (qb *QueryBuilder[T, P]) Query() ([]*T, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
 	sql, args := qb.buildSelectSQL()
-	rows, err := qb.repo.db.QueryContext(qb.ctx, sql, args...)
+	rows, err := qb.repo.query(qb.ctx, qb.repo.reader(qb.ctx), sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +326,7 @@ func (qb *QueryBuilder[T, P]) Query() ([]*T, error) {
 		dest := new(T)
 		
 		// 2. Pede para ele se escanear a partir do *sql.Rows
-		err := P(dest).ScanRow(rows)
+		err := qb.scanEntity(rows, dest)
 		if err != nil {
 			return nil, err // Erro durante o scan da linha
 		}
@@ -133,32 +337,50 @@ func (qb *QueryBuilder[T, P]) Query() ([]*T, error) {
 		return nil, err // Erro pós-iteração (ex: conexão perdida)
 	}
 
+	// Libera a conexão antes das consultas de Preload (necessário dentro de transações)
+	rows.Close()
+	if err := qb.loadRelations(qb.ctx, results); err != nil {
+		return nil, err
+	}
+	if err := afterFindAll[T, P](qb.ctx, results); err != nil {
+		return nil, err
+	}
+
 	return results, nil
 }
 
 // First executa a consulta, adiciona "LIMIT 1" e retorna *sql.Row (para um resultado).
 func (qb *QueryBuilder[T, P]) First() (*T, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
 	if qb.limit == 0 || qb.limit > 1 {
 		qb.limit = 1
 	}
 
 	query, args := qb.buildSelectSQL()
-	row := qb.repo.db.QueryRowContext(qb.ctx, query, args...)
+	row := qb.repo.queryRow(qb.ctx, qb.repo.reader(qb.ctx), query, args...)
 
 	// 1. Cria o destino (ex: new(Usuario))
 	dest := new(T)
 	
 	// 2. Pede ao destino para "se escanear" a partir do *sql.Row
 	//    P(dest) converte &T para P (ex: *Usuario)
-	err := P(dest).ScanRow(row)
+	err := qb.scanEntity(row, dest)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			// Você pode ter um erro customizado aqui
-			return nil, errors.New("registro não encontrado") 
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
 
+	if err := qb.loadRelations(qb.ctx, []*T{dest}); err != nil {
+		return nil, err
+	}
+	if err := afterFind(qb.ctx, P(dest)); err != nil {
+		return nil, err
+	}
+
 	return dest, nil
 }
\ No newline at end of file
@@ -104,4 +104,49 @@ func TestQueryBuilder_Query_Multiple(t *testing.T) {
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestQueryBuilder_ComposedConditions(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	expectedSQL := `SELECT id, name, age FROM "public"."users" WHERE age > $1 AND id IN ($2, $3) AND (name ILIKE $4 OR name IS NULL) OR age BETWEEN $5 AND $6`
+
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WithArgs(18, 1, 2, "%a%", 60, 70).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}))
+
+	_, err := repo.NewQuery(ctx).
+		And("age > ?", 18).
+		In("id", []int{1, 2}).
+		AndCond(AnyOf(ILike("name", "%a%"), IsNull("name"))).
+		OrCond(Between("age", 60, 70)).
+		Query()
+	if err != nil {
+		t.Errorf("QueryBuilder.Query falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestQueryBuilder_NewQuery_NoWhere(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" LIMIT 5`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(1, "Alice", 30))
+
+	results, err := repo.NewQuery(ctx).Limit(5).Query()
+	if err != nil {
+		t.Errorf("QueryBuilder.Query falhou: %s", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Esperava 1 resultado, obteve %d", len(results))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
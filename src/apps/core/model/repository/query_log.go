@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"deskapp/src/internal/utils"
+)
+
+// queryLogger é o logger usado por PrintQuery e pelo LogObserver sem logger próprio.
+var queryLogger = utils.NewLogger()
+
+// LogObserver registra os comandos no utils.Logger. Consultas lentas
+// (QueryEvent.Slow) sempre viram WARNING; com Verbose (modo DEBUG) todos
+// os comandos são registrados, inclusive os que falharam.
+type LogObserver struct {
+	logger  *utils.Logger
+	Verbose bool
+}
+
+// NewLogObserver cria o observer de log. Sem logger, usa o do pacote.
+func NewLogObserver(logger *utils.Logger, verbose bool) *LogObserver {
+	if logger == nil {
+		logger = queryLogger
+	}
+	return &LogObserver{logger: logger, Verbose: verbose}
+}
+
+func (o *LogObserver) ObserveQuery(_ context.Context, ev QueryEvent) {
+	switch {
+	case ev.Slow:
+		o.logger.Warningf("[SQL lenta] %s", formatEvent(ev))
+	case o.Verbose && ev.Err != nil && !errors.Is(ev.Err, sql.ErrNoRows):
+		o.logger.Warningf("[SQL] %s | erro: %v", formatEvent(ev), ev.Err)
+	case o.Verbose:
+		o.logger.Infof("[SQL] %s", formatEvent(ev))
+	}
+}
+
+// formatEvent resume o evento numa linha: duração, linhas, SQL e argumentos.
+func formatEvent(ev QueryEvent) string {
+	line := fmt.Sprintf("%s rows=%d | %s", ev.Duration.Round(10*time.Microsecond), ev.Rows, strings.Join(strings.Fields(ev.SQL), " "))
+	if len(ev.Args) > 0 {
+		line += fmt.Sprintf(" | args=%v", ev.Args)
+	}
+	return line
+}
+
+/*
+QueryRecorder guarda os últimos comandos de cada requisição, para a página
+de depuração. Begin anexa o registro ao ctx; os comandos executados com
+esse ctx (ou derivados dele) entram no registro até End:
+
+	ctx, req := recorder.Begin(ctx, "GET /usuarios")
+	defer recorder.End(req)
+
+O recorder também é um QueryObserver e deve ser registrado com
+SetQueryObserver (ou MultiObserver).
+*/
+type QueryRecorder struct {
+	perRequest int // comandos guardados por requisição
+	keep       int // requisições guardadas
+
+	mu     sync.Mutex // protege recent e as requisições em andamento
+	recent []*RequestQueries
+}
+
+// RequestQueries são os comandos executados durante uma requisição.
+type RequestQueries struct {
+	Label    string
+	Start    time.Time
+	Duration time.Duration
+	Total    int // comandos executados, inclusive os descartados
+	Slow     int
+	Queries  []QueryEvent // os últimos perRequest comandos
+}
+
+type requestQueriesKey struct{}
+
+// NewQueryRecorder guarda os últimos perRequest comandos das últimas keep requisições.
+func NewQueryRecorder(perRequest, keep int) *QueryRecorder {
+	return &QueryRecorder{perRequest: max(perRequest, 1), keep: max(keep, 1)}
+}
+
+// Begin abre o registro de uma requisição e o anexa ao ctx.
+func (q *QueryRecorder) Begin(ctx context.Context, label string) (context.Context, *RequestQueries) {
+	req := &RequestQueries{Label: label, Start: time.Now()}
+	return context.WithValue(ctx, requestQueriesKey{}, req), req
+}
+
+// End fecha o registro e o guarda entre as requisições recentes, se a
+// requisição executou algum comando.
+func (q *QueryRecorder) End(req *RequestQueries) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	req.Duration = time.Since(req.Start)
+	if req.Total == 0 {
+		return
+	}
+	q.recent = append(q.recent, req)
+	if len(q.recent) > q.keep {
+		q.recent = q.recent[len(q.recent)-q.keep:]
+	}
+}
+
+// Recent devolve cópias das requisições recentes, da mais nova para a mais antiga.
+func (q *QueryRecorder) Recent() []RequestQueries {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]RequestQueries, 0, len(q.recent))
+	for i := len(q.recent) - 1; i >= 0; i-- {
+		req := *q.recent[i]
+		req.Queries = slices.Clone(req.Queries)
+		out = append(out, req)
+	}
+	return out
+}
+
+func (q *QueryRecorder) ObserveQuery(ctx context.Context, ev QueryEvent) {
+	req, ok := ctx.Value(requestQueriesKey{}).(*RequestQueries)
+	if !ok {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	req.Total++
+	if ev.Slow {
+		req.Slow++
+	}
+	req.Queries = append(req.Queries, ev)
+	if len(req.Queries) > q.perRequest {
+		req.Queries = req.Queries[len(req.Queries)-q.perRequest:]
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestReplica_RoutesReadsAndWrites(t *testing.T) {
+	primaryDB, primary, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer primaryDB.Close()
+	replicaDB, replica, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer replicaDB.Close()
+
+	repo := NewBaseRepository[MockUser](primaryDB, "users", "public").UseReplica(replicaDB)
+	ctx := t.Context()
+	cols := []string{"id", "name", "age"}
+
+	// Leituras comuns vão para a réplica
+	replica.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users"`)).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, "Ana", 20))
+	replica.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "public"."users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	// Escritas, ReadFromPrimary e transações ficam no primary
+	primary.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."users"`)).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(2, "Bia", 30))
+	primary.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" WHERE id = $1 LIMIT 1`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(2, "Bia", 30))
+	primary.ExpectBegin()
+	primary.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "public"."users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	primary.ExpectCommit()
+
+	if _, err := repo.NewQuery(ctx).Query(); err != nil {
+		t.Fatalf("Query falhou: %s", err)
+	}
+	if _, err := repo.NewQuery(ctx).Count(); err != nil {
+		t.Fatalf("Count falhou: %s", err)
+	}
+	if err := repo.Insert(ctx, &MockUser{Name: sql.NullString{String: "Bia", Valid: true}, Age: 30}); err != nil {
+		t.Fatalf("Insert falhou: %s", err)
+	}
+	if _, err := repo.Where(ReadFromPrimary(ctx), "id = ?", 2).First(); err != nil {
+		t.Fatalf("First no primary falhou: %s", err)
+	}
+	err = repo.WithTx(ctx, func(tx *Tx) error {
+		_, err := repo.NewQuery(tx).Count()
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Count na transação falhou: %s", err)
+	}
+
+	if err := replica.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas da réplica não atendidas: %s", err)
+	}
+	if err := primary.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do primary não atendidas: %s", err)
+	}
+}
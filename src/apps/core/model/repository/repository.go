@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"deskapp/src/apps/core/model/entities"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -12,24 +13,42 @@ import (
 
 type IBaseRepository[T any, P interface { *T; entities.Entity }] interface {
 	GetDB() *sql.DB
-	Where(ctx context.Context, queryFragment string, arg any) IQueryBuilder[T, P]
+	NewQuery(ctx context.Context) IQueryBuilder[T, P]
+	Where(ctx context.Context, queryFragment string, args ...any) IQueryBuilder[T, P]
+	WithTx(ctx context.Context, fn TxFunc) error
 	Insert(ctx context.Context, entity P) error
 	Update(ctx context.Context, entity P) error
 	Delete(ctx context.Context, entity P) error 
+	ForceDelete(ctx context.Context, entity P) error
+	Restore(ctx context.Context, entity P) error
+	InsertMany(ctx context.Context, items []P) (int64, error)
+	CopyMany(ctx context.Context, items []P) (int64, error)
+	Upsert(ctx context.Context, entity P, conflictCols, updateCols []string) error
+	UpdateWhere(query IQueryBuilder[T, P], values map[string]any) (int64, error)
+	DeleteWhere(query IQueryBuilder[T, P]) (int64, error)
+	ForceDeleteWhere(query IQueryBuilder[T, P]) (int64, error)
 }
 
 type BaseRepository[T any, P interface { *T; entities.Entity }] struct {
-	db     *sql.DB // Voltamos ao sql.DB padrão!
-	table  string
-	schema string
+	db       *sql.DB       // Voltamos ao sql.DB padrão!
+	replica  *sql.DB       // leituras do QueryBuilder, quando configurada (UseReplica)
+	dialect  Dialect       // SQL específico do banco (ver dialect.go)
+	observer QueryObserver // substitui o observer padrão (UseObserver)
+	table    string
+	schema   string
 }
 
+// primaryReadKey marca contextos cujas leituras devem ir ao primary.
+type primaryReadKey struct{}
+
 // getFullTableName é um helper interno para formatar "schema"."table".
 func (r *BaseRepository[T, P]) getFullTableName() string {
-	if r.schema != "" {
-		return fmt.Sprintf(`"%s"."%s"`, r.schema, r.table)
-	}
-	return fmt.Sprintf(`"%s"`, r.table)
+	return r.dialect.Table(r.schema, r.table)
+}
+
+// Dialect expõe o dialeto SQL usado pelo repositório.
+func (r *BaseRepository[T, P]) Dialect() Dialect {
+	return r.dialect
 }
 
 // GetDB expõe o executor para uso direto, se necessário.
@@ -37,20 +56,82 @@ func (r *BaseRepository[T, P]) GetDB() *sql.DB {
 	return r.db
 }
 
-func (r *BaseRepository[T, P]) Where(ctx context.Context, queryFragment string, arg any) IQueryBuilder[T, P] {
+// executor retorna a transação do ctx aberta em r.db ou, na falta dela, o *sql.DB.
+func (r *BaseRepository[T, P]) executor(ctx context.Context) Executor {
+	if tx, ok := txFor(ctx, r.db); ok {
+		return tx
+	}
+	return r.db
+}
+
+// UseReplica direciona as leituras do QueryBuilder (Query, First, Count,
+// Paginate...) para uma réplica. Escritas e transações continuam no db
+// principal, assim como leituras feitas dentro de WithTx.
+//
+//	repo := NewRelatorioRepository(app.GetDB())
+//	repo.UseReplica(app.GetReplicaDB())
+func (r *BaseRepository[T, P]) UseReplica(replica *sql.DB) *BaseRepository[T, P] {
+	r.replica = replica
+	return r
+}
+
+// ReadFromPrimary marca o ctx para que as leituras ignorem a réplica,
+// útil para ler logo após gravar sem esperar a replicação.
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+// reader escolhe onde executar leituras: a transação do ctx aberta em r.db,
+// o primary (ReadFromPrimary) ou a réplica, quando configurada.
+func (r *BaseRepository[T, P]) reader(ctx context.Context) Executor {
+	if tx, ok := txFor(ctx, r.db); ok {
+		return tx
+	}
+	if r.replica != nil && (ctx == nil || ctx.Value(primaryReadKey{}) == nil) {
+		return r.replica
+	}
+	return r.db
+}
+
+// WithTx executa fn em uma transação. Passe o *Tx recebido como ctx para
+// este ou qualquer outro repositório e todos participarão da mesma transação.
+// Chamadas aninhadas viram SAVEPOINTs.
+func (r *BaseRepository[T, P]) WithTx(ctx context.Context, fn TxFunc) error {
+	return WithTx(ctx, r.db, fn)
+}
+
+// NewQuery cria um QueryBuilder sem condições (SELECT de toda a tabela),
+// útil quando os filtros são montados dinamicamente.
+func (r *BaseRepository[T, P]) NewQuery(ctx context.Context) IQueryBuilder[T, P] {
 	return &QueryBuilder[T, P]{
-		repo:    r,
-		ctx:     ctx,
-		wheres:  []string{queryFragment},
-		args:    []any{arg},
+		repo: r,
+		ctx:  ctx,
 	}
 }
 
+// Where cria um QueryBuilder com a condição inicial. Use '?' como placeholder.
+func (r *BaseRepository[T, P]) Where(ctx context.Context, queryFragment string, args ...any) IQueryBuilder[T, P] {
+	return r.NewQuery(ctx).And(queryFragment, args...)
+}
+
 /* 
 getEntityColumnMap usa reflexão para mapear "nome_da_coluna" -> valor
  Ex: "email" -> "teste@exemplo.com", "id" -> 123
+ Entidades com Values() (entities.ColumnValuer) dispensam a reflexão.
 */
 func (r *BaseRepository[T, P]) getEntityColumnMap(entity P) (map[string]any, error) {
+	if valuer, ok := any(entity).(entities.ColumnValuer); ok {
+		cols, values := entity.Columns(), valuer.Values()
+		if len(cols) != len(values) {
+			return nil, fmt.Errorf("Values() retornou %d valores para %d colunas", len(values), len(cols))
+		}
+		colMap := make(map[string]any, len(cols))
+		for i, col := range cols {
+			colMap[col] = values[i]
+		}
+		return colMap, nil
+	}
+
 	// P é um ponteiro para T (ex: *Usuario), então .Elem() pega a struct (Usuario)
 	v := reflect.ValueOf(entity).Elem()
 	if v.Kind() != reflect.Struct {
@@ -61,63 +142,100 @@ func (r *BaseRepository[T, P]) getEntityColumnMap(entity P) (map[string]any, err
 	colMap := make(map[string]any)
 	for i := 0; i < v.NumField(); i++ {
 		field := t.Field(i)
-		// Usa a tag 'json' como o nome da coluna,
-		// exatamente como seu 'tablemap' faz
-		colName := field.Tag.Get("json")
-		if colName != "" {
-			colMap[colName] = v.Field(i).Interface()
+		// Usa a tag 'db' (ou 'json', nas entidades antigas) como o nome da coluna
+		if tag := parseColumnTag(field); tag.isColumn() {
+			colMap[tag.name] = v.Field(i).Interface()
 		}
 	}
 	return colMap, nil
 }
 
-// Insert constrói e executa um INSERT
+// Insert constrói e executa um INSERT ... RETURNING.
+// Colunas sem valor (nil, sql.Null* inválido, time.Time zerado e PK simples zerada)
+// ficam de fora para que os DEFAULTs do banco sejam aplicados, e a linha
+// gravada é escaneada de volta na entidade via ScanRow.
+// created_at/updated_at, se existirem, são preenchidos antes do INSERT.
 func (r *BaseRepository[T, P]) Insert(ctx context.Context, entity P) error {
-	tableName := r.getFullTableName()
+	if err := beforeInsert(ctx, entity); err != nil {
+		return err
+	}
+	touchTimestamps(entity, true)
 
-	// 1. Pega os valores da entidade usando reflexão
-	colsMap, err := r.getEntityColumnMap(entity)
+	// 1. Monta colunas e valores, na ordem de Columns()
+	into, values, err := r.insertValues(entity)
 	if err != nil {
 		return err
 	}
 
-	// 2. Pega a *ordem* das colunas (do método gerado)
-	cols := any(entity).(interface{ Columns() []string }).Columns()
+	// 2. Constrói a query
+	query := r.insertSQL(into, values) + r.dialect.Returning(entity.Columns())
 
-	values := make([]any, 0, len(cols))
-	placeholders := make([]string, 0, len(cols))
-	into := make([]string, 0, len(cols))
+	// 3. Executa e popula a entidade com o que o banco gravou
+	row := r.queryRow(ctx, r.executor(ctx), query, values...)
+	if err := entity.ScanRow(row); err != nil {
+		return fmt.Errorf("erro ao inserir: %w", translateError(err))
+	}
+	return afterInsert(ctx, entity)
+}
+
+// insertValues separa as colunas preenchidas da entidade e seus valores.
+// PK simples zerada (serial/default), valores não preenchidos e colunas
+// readonly/omitinsert ficam de fora.
+func (r *BaseRepository[T, P]) insertValues(entity P) ([]string, []any, error) {
+	// Pega os valores da entidade usando reflexão
+	colsMap, err := r.getEntityColumnMap(entity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Pega a *ordem* das colunas (do método gerado)
+	cols := entity.Columns()
+	meta := metaOf(entity)
 
-	// 3. Monta os slices de valores e placeholders na ordem correta
+	into := make([]string, 0, len(cols))
+	values := make([]any, 0, len(cols))
 	for _, colName := range cols {
-		// Ignora 'id' em inserts (assumindo que é auto-increment/default)
-		if colName == "id" {
+		value := colsMap[colName]
+		if meta.skipOnInsert(colName) || (meta.isGeneratedKey(colName) && isZeroValue(value)) || isUnsetValue(value) {
 			continue
 		}
 		into = append(into, colName)
-		values = append(values, colsMap[colName])
-		placeholders = append(placeholders, fmt.Sprintf("$%d", len(values))) // len(values) é 1-based
+		values = append(values, value)
 	}
+	return into, values, nil
+}
 
-	// 4. Constrói a query
-	query := fmt.Sprintf(
+// insertSQL monta "INSERT INTO tabela (cols) VALUES ($1, ...)" ou DEFAULT VALUES.
+func (r *BaseRepository[T, P]) insertSQL(into []string, values []any) string {
+	tableName := r.getFullTableName()
+	if len(into) == 0 {
+		return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", tableName)
+	}
+
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = r.dialect.Placeholder(i + 1) // placeholders são 1-based
+	}
+	return fmt.Sprintf(
 		"INSERT INTO %s (%s) VALUES (%s)",
 		tableName,
 		strings.Join(into, ", "),
 		strings.Join(placeholders, ", "),
 	)
-	fmt.Println(query)
-	// 5. Executa
-	_, err = r.db.ExecContext(ctx, query, values...)
-	if err != nil {
-		return fmt.Errorf("erro ao inserir: %w", err)
-	}
-	return nil
 }
 
-// Update constrói e executa um UPDATE
+// Update constrói e executa um UPDATE usando a PK declarada pela entidade.
+// updated_at é renovado, created_at e deleted_at nunca são sobrescritos e
+// linhas excluídas logicamente não são alteradas. Com coluna de versão
+// (ver version.go) o UPDATE só vale para a versão lida e retorna
+// ErrStale se outro usuário gravou antes.
 func (r *BaseRepository[T, P]) Update(ctx context.Context, entity P) error {
 	tableName := r.getFullTableName()
+	meta := metaOf(entity)
+	if err := beforeUpdate(ctx, entity); err != nil {
+		return err
+	}
+	touchTimestamps(entity, false)
 
 	// 1. Pega os valores da entidade
 	colsMap, err := r.getEntityColumnMap(entity)
@@ -126,51 +244,86 @@ func (r *BaseRepository[T, P]) Update(ctx context.Context, entity P) error {
 	}
 
 	// 2. Pega a *ordem* das colunas
-	cols := any(entity).(interface{ Columns() []string }).Columns()
+	cols := entity.Columns()
 
 	setClauses := make([]string, 0)
 	values := make([]any, 0)
-	var pkValue any
-	placeholderIndex := 1 // Placeholders são 1-based
 
-	// 3. Monta a cláusula SET, separando o 'id'
+	// 3. Monta a cláusula SET, separando as colunas da PK
 	for _, colName := range cols {
-		if colName == "id" {
-			pkValue = colsMap[colName]
+		if meta.isPrimaryKey(colName) || meta.readonly[colName] || colName == ColumnCreatedAt || colName == ColumnDeletedAt {
+			continue
+		}
+		if colName == meta.versionColumn {
+			setClauses = append(setClauses, fmt.Sprintf("%s = %s + 1", colName, colName))
 			continue
 		}
 
-		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", colName, placeholderIndex))
 		values = append(values, colsMap[colName])
-		placeholderIndex++
+		setClauses = append(setClauses, fmt.Sprintf("%s = %s", colName, r.dialect.Placeholder(len(values))))
+	}
+	if len(setClauses) == 0 {
+		// Só PK, readonly, created_at e deleted_at: não há o que gravar
+		return fmt.Errorf("erro ao atualizar: nenhuma coluna atualizável em %s", tableName)
 	}
 
-	// 4. Valida se a PK existe
-	if pkValue == nil {
-		return fmt.Errorf("entidade sem 'id' para atualização")
+	// 4. Monta o WHERE pela PK (os placeholders continuam após os do SET)
+	where, pkValues, err := primaryKeyWhere(r.dialect, meta, colsMap, len(values)+1)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar: %w", err)
+	}
+	values = append(values, pkValues...)
+	if meta.softDelete {
+		where += " AND " + ColumnDeletedAt + " IS NULL"
+	}
+	if meta.versionColumn != "" {
+		values = append(values, colsMap[meta.versionColumn])
+		where += fmt.Sprintf(" AND %s = %s", meta.versionColumn, r.dialect.Placeholder(len(values)))
 	}
-	
-	// Adiciona o valor do ID no final da lista de argumentos
-	values = append(values, pkValue)
 
 	// 5. Constrói a query
 	query := fmt.Sprintf(
-		"UPDATE %s SET %s WHERE id = $%d",
+		"UPDATE %s SET %s WHERE %s",
 		tableName,
 		strings.Join(setClauses, ", "),
-		placeholderIndex, // O placeholder final é o do ID
+		where,
 	)
-
-	// 6. Executa
-	_, err = r.db.ExecContext(ctx, query, values...)
-	if err != nil {
-		return fmt.Errorf("erro ao atualizar: %w", err)
+	query += r.dialect.Returning(cols)
+
+	// 6. Executa e recarrega a entidade (triggers, colunas calculadas, etc.)
+	row := r.queryRow(ctx, r.executor(ctx), query, values...)
+	if err := entity.ScanRow(row); err != nil {
+		// Nenhuma linha com a versão lida: alguém gravou antes (ou excluiu)
+		if meta.versionColumn != "" && errors.Is(err, sql.ErrNoRows) {
+			return ErrStale
+		}
+		return fmt.Errorf("erro ao atualizar: %w", translateError(err))
 	}
-	return nil
+	return afterUpdate(ctx, entity)
 }
 
-// Delete constrói e executa um DELETE
+// Delete exclui a entidade pela PK. Com a coluna deleted_at a exclusão é
+// lógica (ver softdelete.go); use ForceDelete para apagar a linha de fato.
 func (r *BaseRepository[T, P]) Delete(ctx context.Context, entity P) error {
+	if err := beforeDelete(ctx, entity); err != nil {
+		return err
+	}
+	if metaOf(entity).softDelete {
+		return r.softDelete(ctx, entity)
+	}
+	return r.forceDelete(ctx, entity)
+}
+
+// ForceDelete apaga a linha de fato, mesmo em entidades com deleted_at.
+func (r *BaseRepository[T, P]) ForceDelete(ctx context.Context, entity P) error {
+	if err := beforeDelete(ctx, entity); err != nil {
+		return err
+	}
+	return r.forceDelete(ctx, entity)
+}
+
+// forceDelete constrói e executa um DELETE usando a PK declarada pela entidade
+func (r *BaseRepository[T, P]) forceDelete(ctx context.Context, entity P) error {
 	tableName := r.getFullTableName()
 
 	// 1. Pega os valores da entidade
@@ -179,29 +332,51 @@ func (r *BaseRepository[T, P]) Delete(ctx context.Context, entity P) error {
 		return err
 	}
 
-	// 2. Valida e pega o valor da PK
-	pkValue, ok := colsMap["id"]
-	if !ok || pkValue == nil {
-		return fmt.Errorf("entidade sem 'id' para exclusão")
+	// 2. Valida e monta o WHERE pela PK
+	where, pkValues, err := primaryKeyWhere(r.dialect, metaOf(entity), colsMap, 1)
+	if err != nil {
+		return fmt.Errorf("erro ao excluir: %w", err)
 	}
 
 	// 3. Constrói a query
-	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, where)
 
 	// 4. Executa
-	_, err = r.db.ExecContext(ctx, query, pkValue)
+	_, err = r.exec(ctx, r.executor(ctx), query, pkValues...)
 	if err != nil {
-		return fmt.Errorf("erro ao excluir: %w", err)
+		return fmt.Errorf("erro ao excluir: %w", translateError(err))
 	}
 	return nil
 }
 
+/*
+primaryKeyWhere monta "pk1 = $n AND pk2 = $n+1" a partir dos valores da entidade.
+Ex: ["empresa_id", "codigo"] -> "empresa_id = $3 AND codigo = $4"
+*/
+func primaryKeyWhere(d Dialect, meta *entityMeta, colsMap map[string]any, startIndex int) (string, []any, error) {
+	if len(meta.primaryKeys) == 0 {
+		return "", nil, fmt.Errorf("entidade sem chave primária declarada")
+	}
+
+	clauses := make([]string, 0, len(meta.primaryKeys))
+	values := make([]any, 0, len(meta.primaryKeys))
+	for i, pk := range meta.primaryKeys {
+		value, ok := colsMap[pk]
+		if !ok || isUnsetValue(value) {
+			return "", nil, fmt.Errorf("entidade sem valor para a chave primária '%s'", pk)
+		}
+		clauses = append(clauses, fmt.Sprintf("%s = %s", pk, d.Placeholder(startIndex+i)))
+		values = append(values, value)
+	}
+	return strings.Join(clauses, " AND "), values, nil
+}
 
 func NewBaseRepository[T any, P interface { *T; entities.Entity }](db *sql.DB, table string, schema string) *BaseRepository[T, P] {
 	return &BaseRepository[T, P]{
-		db:     db,
-		table:  table,
-		schema: schema,
+		db:      db,
+		dialect: DialectOf(db),
+		table:   table,
+		schema:  schema,
 	}
 }
 
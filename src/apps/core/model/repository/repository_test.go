@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"deskapp/src/apps/core/model/entities"
 
@@ -72,16 +74,46 @@ func TestInsert(t *testing.T) {
 
 	// Query esperada (note que 'id' é pulado, como na sua lógica)
 	// Usamos MustCompile para tratar a query como Regexp
-	expectedSQL := `INSERT INTO "public"."users" (name, age) VALUES ($1, $2)`
-	mock.ExpectExec(regexp.QuoteMeta(expectedSQL)).
+	expectedSQL := `INSERT INTO "public"."users" (name, age) VALUES ($1, $2) RETURNING id, name, age`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
 		WithArgs("Test User", 30).
-		WillReturnResult(sqlmock.NewResult(1, 1)) 
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(42, "Test User", 30))
 
 	err := repo.Insert(ctx, user)
 	if err != nil {
 		t.Errorf("Insert falhou: %s", err)
 	}
 
+	// O id gerado pelo banco deve voltar para a entidade
+	if user.ID != 42 {
+		t.Errorf("Esperava ID=42 após o insert, obteve %d", user.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestInsert_OmitsUnsetColumns(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	// Name inválido (NULL) fica de fora para o DEFAULT do banco ser aplicado
+	user := &MockUser{Age: 18}
+
+	expectedSQL := `INSERT INTO "public"."users" (age) VALUES ($1) RETURNING id, name, age`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(7, "anônimo", 18))
+
+	if err := repo.Insert(ctx, user); err != nil {
+		t.Errorf("Insert falhou: %s", err)
+	}
+
+	if user.ID != 7 || user.Name.String != "anônimo" {
+		t.Errorf("Entidade não recebeu os valores padrão: %+v", user)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
 	}
@@ -98,10 +130,10 @@ func TestUpdate(t *testing.T) {
 	}
 
 	// Query esperada (note a ordem dos placeholders)
-	expectedSQL := `UPDATE "public"."users" SET name = $1, age = $2 WHERE id = $3`
-	mock.ExpectExec(regexp.QuoteMeta(expectedSQL)).
+	expectedSQL := `UPDATE "public"."users" SET name = $1, age = $2 WHERE id = $3 RETURNING id, name, age`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
 		WithArgs("Updated User", 31, int64(123)).
-		WillReturnResult(sqlmock.NewResult(0, 1)) 
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).AddRow(123, "Updated User", 31))
 
 	err := repo.Update(ctx, user)
 	if err != nil {
@@ -133,3 +165,158 @@ func TestDelete(t *testing.T) {
 		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
 	}
 }
+
+// MockItem usa chave composta declarada via PrimaryKey()
+type MockItem struct {
+	PedidoID int64  `json:"pedido_id"`
+	Linha    int    `json:"linha"`
+	Produto  string `json:"produto"`
+}
+
+func (m *MockItem) Columns() []string { return []string{"pedido_id", "linha", "produto"} }
+
+func (m *MockItem) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.PedidoID, &m.Linha, &m.Produto)
+}
+
+func (m *MockItem) PrimaryKey() []string { return []string{"pedido_id", "linha"} }
+
+// MockProduto usa uma PK que não se chama "id", declarada via tag
+type MockProduto struct {
+	Codigo    string `json:"codigo" pk:"true"`
+	Descricao string `json:"descricao"`
+}
+
+func (m *MockProduto) Columns() []string { return []string{"codigo", "descricao"} }
+
+func (m *MockProduto) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.Codigo, &m.Descricao)
+}
+
+func TestUpdate_CompositePrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockItem](db, "itens", "public")
+
+	expectedSQL := `UPDATE "public"."itens" SET produto = $1 WHERE pedido_id = $2 AND linha = $3 RETURNING pedido_id, linha, produto`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WithArgs("Caneta", int64(10), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"pedido_id", "linha", "produto"}).AddRow(10, 2, "Caneta"))
+
+	if err := repo.Update(context.Background(), &MockItem{PedidoID: 10, Linha: 2, Produto: "Caneta"}); err != nil {
+		t.Errorf("Update falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+// MockVinculo só tem a PK composta e created_at: nada a atualizar
+type MockVinculo struct {
+	UsuarioID int64     `json:"usuario_id"`
+	GrupoID   int64     `json:"grupo_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (m *MockVinculo) Columns() []string    { return []string{"usuario_id", "grupo_id", "created_at"} }
+func (m *MockVinculo) PrimaryKey() []string { return []string{"usuario_id", "grupo_id"} }
+
+func (m *MockVinculo) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.UsuarioID, &m.GrupoID, &m.CreatedAt)
+}
+
+func TestUpdate_NoUpdatableColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockVinculo](db, "usuarios_grupos", "public")
+
+	// Nenhuma query é esperada: o erro vem antes de montar "SET  WHERE"
+	err = repo.Update(context.Background(), &MockVinculo{UsuarioID: 1, GrupoID: 2})
+	if err == nil || !strings.Contains(err.Error(), "nenhuma coluna atualizável") {
+		t.Errorf("Esperava erro de nenhuma coluna atualizável, obteve %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestInsertAndDelete_TaggedPrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockProduto](db, "produtos", "")
+	ctx := context.Background()
+
+	// A PK informada pelo usuário não é descartada no insert
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "produtos" (codigo, descricao) VALUES ($1, $2) RETURNING codigo, descricao`)).
+		WithArgs("P-01", "Papel").
+		WillReturnRows(sqlmock.NewRows([]string{"codigo", "descricao"}).AddRow("P-01", "Papel"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "produtos" WHERE codigo = $1`)).
+		WithArgs("P-01").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	produto := &MockProduto{Codigo: "P-01", Descricao: "Papel"}
+	if err := repo.Insert(ctx, produto); err != nil {
+		t.Errorf("Insert falhou: %s", err)
+	}
+	if err := repo.Delete(ctx, produto); err != nil {
+		t.Errorf("Delete falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+// MockTag não tem tags json: os valores só chegam ao repositório via Values()
+type MockTag struct {
+	ID   int64
+	Nome string
+}
+
+func (m *MockTag) Columns() []string { return []string{"id", "nome"} }
+func (m *MockTag) Values() []any     { return []any{m.ID, m.Nome} }
+
+func (m *MockTag) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.Nome)
+}
+
+func TestInsertAndUpdate_PreferValues(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockTag](db, "tags", "")
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags" (nome) VALUES ($1) RETURNING id, nome`)).
+		WithArgs("go").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nome"}).AddRow(5, "go"))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "tags" SET nome = $1 WHERE id = $2 RETURNING id, nome`)).
+		WithArgs("golang", int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nome"}).AddRow(5, "golang"))
+
+	tag := &MockTag{Nome: "go"}
+	if err := repo.Insert(ctx, tag); err != nil {
+		t.Fatalf("Insert falhou: %s", err)
+	}
+	tag.Nome = "golang"
+	if err := repo.Update(ctx, tag); err != nil {
+		t.Fatalf("Update falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// rowIterator é o subconjunto de *sql.Rows usado pelos scans em lote.
+type rowIterator interface {
+	DBScanner
+	Columns() ([]string, error)
+	Next() bool
+	Err() error
+}
+
+// scanRowsInto escaneia todas as linhas em dest (*[]Struct, *[]*Struct ou
+// *[]map[string]any), casando colunas pelo nome.
+func scanRowsInto(rows rowIterator, dest any) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("scan: destino deve ser ponteiro para slice, recebido %T", dest)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	isPtr := elemType.Kind() == reflect.Pointer
+	baseType := elemType
+	if isPtr {
+		baseType = elemType.Elem()
+	}
+
+	switch {
+	case baseType.Kind() == reflect.Map && baseType.Key().Kind() == reflect.String:
+		for rows.Next() {
+			row, err := scanMap(rows, cols)
+			if err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, reflect.ValueOf(row)))
+		}
+	case baseType.Kind() == reflect.Struct:
+		fields := structFieldsByColumn(baseType)
+		for rows.Next() {
+			item := reflect.New(baseType)
+			targets := make([]any, len(cols))
+			for i, col := range cols {
+				if index, ok := fields[col]; ok {
+					targets[i] = item.Elem().FieldByIndex(index).Addr().Interface()
+				} else {
+					targets[i] = new(any) // coluna sem campo correspondente é descartada
+				}
+			}
+			if err := rows.Scan(targets...); err != nil {
+				return err
+			}
+			if isPtr {
+				slice.Set(reflect.Append(slice, item))
+			} else {
+				slice.Set(reflect.Append(slice, item.Elem()))
+			}
+		}
+	default:
+		return fmt.Errorf("scan: tipo de destino não suportado: %s", elemType)
+	}
+
+	return rows.Err()
+}
+
+// scanMap lê a linha atual como map coluna -> valor.
+func scanMap(rows DBScanner, cols []string) (map[string]any, error) {
+	values := make([]any, len(cols))
+	targets := make([]any, len(cols))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]any, len(cols))
+	for i, col := range cols {
+		// Drivers devolvem texto/numeric como []byte; string é mais útil em templates
+		if b, ok := values[i].([]byte); ok {
+			row[col] = string(b)
+		} else {
+			row[col] = values[i]
+		}
+	}
+	return row, nil
+}
+
+// structFieldsByColumn mapeia nome de coluna -> índice do campo,
+// usando a tag `db`, a tag `json` ou o nome do campo em minúsculas.
+func structFieldsByColumn(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := parseColumnTag(field).name
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Index
+	}
+	return fields
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+Exclusão lógica: entidades com a coluna deleted_at em Columns() não são
+apagadas por Delete/DeleteWhere, apenas marcadas com o horário da exclusão,
+e o QueryBuilder passa a ignorar as linhas marcadas:
+
+	repo.Delete(ctx, usuario)                              // UPDATE ... SET deleted_at = now
+	repo.NewQuery(ctx).Query()                             // só os ativos
+	repo.NewQuery(ctx).WithTrashed().Query()               // todos
+	repo.NewQuery(ctx).OnlyTrashed().Query()               // só os excluídos
+	repo.Restore(ctx, usuario)                             // desfaz a exclusão
+	repo.ForceDelete(ctx, usuario)                         // DELETE de verdade
+*/
+
+// trashedMode define como o QueryBuilder trata as linhas excluídas logicamente.
+type trashedMode uint8
+
+const (
+	excludeTrashed trashedMode = iota // padrão: deleted_at IS NULL
+	withTrashed                       // sem filtro
+	onlyTrashed                       // deleted_at IS NOT NULL
+)
+
+// WithTrashed inclui as linhas excluídas logicamente no resultado.
+func (qb *QueryBuilder[T, P]) WithTrashed() IQueryBuilder[T, P] {
+	qb.trashed = withTrashed
+	return qb
+}
+
+// OnlyTrashed retorna apenas as linhas excluídas logicamente.
+func (qb *QueryBuilder[T, P]) OnlyTrashed() IQueryBuilder[T, P] {
+	if !qb.meta().softDelete {
+		qb.fail(fmt.Errorf("OnlyTrashed: entidade sem a coluna %s", ColumnDeletedAt))
+		return qb
+	}
+	qb.trashed = onlyTrashed
+	return qb
+}
+
+// meta devolve os metadados da entidade do builder.
+func (qb *QueryBuilder[T, P]) meta() *entityMeta {
+	var model T
+	return metaOf(P(&model))
+}
+
+// trashedScope devolve o filtro de exclusão lógica ou "" se não se aplicar.
+func (qb *QueryBuilder[T, P]) trashedScope() string {
+	if !qb.meta().softDelete {
+		return ""
+	}
+	switch qb.trashed {
+	case excludeTrashed:
+		return ColumnDeletedAt + " IS NULL"
+	case onlyTrashed:
+		return ColumnDeletedAt + " IS NOT NULL"
+	}
+	return ""
+}
+
+// buildWhere junta o filtro de exclusão lógica às condições do builder.
+// As condições vão entre parênteses para que um OR não escape do filtro.
+func (qb *QueryBuilder[T, P]) buildWhere(args *[]any) string {
+	where := buildTerms(qb.repo.dialect, qb.wheres, args)
+	scope := qb.trashedScope()
+	switch {
+	case scope == "":
+		return where
+	case where == "":
+		return scope
+	}
+	return scope + " AND (" + where + ")"
+}
+
+// softDelete marca a entidade como excluída (deleted_at = agora).
+func (r *BaseRepository[T, P]) softDelete(ctx context.Context, entity P) error {
+	meta := metaOf(entity)
+	colsMap, err := r.getEntityColumnMap(entity)
+	if err != nil {
+		return err
+	}
+
+	where, pkValues, err := primaryKeyWhere(r.dialect, meta, colsMap, 2)
+	if err != nil {
+		return fmt.Errorf("erro ao excluir: %w", err)
+	}
+
+	now := nowFunc()
+	query := fmt.Sprintf(
+		"UPDATE %s SET %s = %s WHERE %s AND %s IS NULL",
+		r.getFullTableName(), ColumnDeletedAt, r.dialect.Placeholder(1), where, ColumnDeletedAt,
+	)
+	if _, err := r.exec(ctx, r.executor(ctx), query, append([]any{now}, pkValues...)...); err != nil {
+		return fmt.Errorf("erro ao excluir: %w", translateError(err))
+	}
+
+	if f, ok := meta.field(entity, ColumnDeletedAt); ok {
+		setTimeField(f, now)
+	}
+	return nil
+}
+
+// Restore desfaz a exclusão lógica da entidade (deleted_at = NULL).
+func (r *BaseRepository[T, P]) Restore(ctx context.Context, entity P) error {
+	meta := metaOf(entity)
+	if !meta.softDelete {
+		return fmt.Errorf("erro ao restaurar: entidade sem a coluna %s", ColumnDeletedAt)
+	}
+	colsMap, err := r.getEntityColumnMap(entity)
+	if err != nil {
+		return err
+	}
+
+	where, pkValues, err := primaryKeyWhere(r.dialect, meta, colsMap, 1)
+	if err != nil {
+		return fmt.Errorf("erro ao restaurar: %w", err)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s", r.getFullTableName(), ColumnDeletedAt, where)
+	if _, err := r.exec(ctx, r.executor(ctx), query, pkValues...); err != nil {
+		return fmt.Errorf("erro ao restaurar: %w", translateError(err))
+	}
+
+	if f, ok := meta.field(entity, ColumnDeletedAt); ok {
+		setTimeField(f, time.Time{})
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"deskapp/src/apps/core/model/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// MockPost tem timestamps e exclusão lógica
+type MockPost struct {
+	ID        int64        `json:"id"`
+	Titulo    string       `json:"titulo"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt *time.Time   `json:"updated_at"`
+	DeletedAt sql.NullTime `json:"deleted_at"`
+}
+
+func (m *MockPost) Columns() []string {
+	return []string{"id", "titulo", "created_at", "updated_at", "deleted_at"}
+}
+
+func (m *MockPost) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.Titulo, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+}
+
+var fixedNow = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func setupPosts(t *testing.T) (*BaseRepository[MockPost, *MockPost], sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	nowFunc = func() time.Time { return fixedNow }
+	t.Cleanup(func() { nowFunc = time.Now })
+
+	return NewBaseRepository[MockPost](db, "posts", "public"), mock
+}
+
+func postRow(id int64, deletedAt any) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "titulo", "created_at", "updated_at", "deleted_at"}).
+		AddRow(id, "Olá", fixedNow, fixedNow, deletedAt)
+}
+
+func TestSoftDelete_InsertFillsTimestamps(t *testing.T) {
+	repo, mock := setupPosts(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "public"."posts" (titulo, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id, titulo, created_at, updated_at, deleted_at`)).
+		WithArgs("Olá", fixedNow, &fixedNow).
+		WillReturnRows(postRow(1, nil))
+
+	post := &MockPost{Titulo: "Olá"}
+	if err := repo.Insert(t.Context(), post); err != nil {
+		t.Fatalf("Insert falhou: %s", err)
+	}
+	if !post.CreatedAt.Equal(fixedNow) || post.UpdatedAt == nil {
+		t.Errorf("Timestamps não preenchidos: %+v", post)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestSoftDelete_UpdateKeepsCreatedAt(t *testing.T) {
+	repo, mock := setupPosts(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "public"."posts" SET titulo = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, titulo, created_at, updated_at, deleted_at`)).
+		WithArgs("Novo", &fixedNow, int64(1)).
+		WillReturnRows(postRow(1, nil))
+
+	if err := repo.Update(t.Context(), &MockPost{ID: 1, Titulo: "Novo"}); err != nil {
+		t.Fatalf("Update falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestSoftDelete_DeleteMarksRow(t *testing.T) {
+	repo, mock := setupPosts(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "public"."posts" SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`)).
+		WithArgs(fixedNow, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "public"."posts" SET deleted_at = NULL WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."posts" WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	post := &MockPost{ID: 1}
+	if err := repo.Delete(t.Context(), post); err != nil {
+		t.Fatalf("Delete falhou: %s", err)
+	}
+	if !post.DeletedAt.Valid {
+		t.Error("deleted_at deveria ter sido preenchido na entidade")
+	}
+	if err := repo.Restore(t.Context(), post); err != nil {
+		t.Fatalf("Restore falhou: %s", err)
+	}
+	if post.DeletedAt.Valid {
+		t.Error("deleted_at deveria ter sido limpo na entidade")
+	}
+	if err := repo.ForceDelete(t.Context(), post); err != nil {
+		t.Fatalf("ForceDelete falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestSoftDelete_QueryScopes(t *testing.T) {
+	repo, mock := setupPosts(t)
+	ctx := t.Context()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, titulo, created_at, updated_at, deleted_at FROM "public"."posts" WHERE deleted_at IS NULL AND (titulo = $1 OR id = $2)`)).
+		WithArgs("Olá", 1).
+		WillReturnRows(postRow(1, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, titulo, created_at, updated_at, deleted_at FROM "public"."posts" WHERE deleted_at IS NOT NULL`)).
+		WillReturnRows(postRow(2, fixedNow))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM "public"."posts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	if _, err := repo.Where(ctx, "titulo = ?", "Olá").Or("id = ?", 1).Query(); err != nil {
+		t.Fatalf("Query padrão falhou: %s", err)
+	}
+	if _, err := repo.NewQuery(ctx).OnlyTrashed().Query(); err != nil {
+		t.Fatalf("OnlyTrashed falhou: %s", err)
+	}
+	if total, err := repo.NewQuery(ctx).WithTrashed().Count(); err != nil || total != 2 {
+		t.Fatalf("WithTrashed falhou: %d, %v", total, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestSoftDelete_DeleteWhere(t *testing.T) {
+	repo, mock := setupPosts(t)
+	ctx := t.Context()
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "public"."posts" SET deleted_at = $2 WHERE deleted_at IS NULL AND (titulo = $1)`)).
+		WithArgs("spam", fixedNow).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "public"."posts" SET deleted_at = $2 WHERE deleted_at IS NULL AND (titulo = $1)`)).
+		WithArgs("spam", fixedNow).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."posts" WHERE deleted_at IS NOT NULL AND (deleted_at < $1)`)).
+		WithArgs(fixedNow).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if n, err := repo.DeleteWhere(repo.Where(ctx, "titulo = ?", "spam")); err != nil || n != 3 {
+		t.Fatalf("DeleteWhere falhou: %d, %v", n, err)
+	}
+	// Com $n manual deleted_at não toma o lugar do argumento do WHERE
+	if n, err := repo.DeleteWhere(repo.Where(ctx, "titulo = $1", "spam")); err != nil || n != 3 {
+		t.Fatalf("DeleteWhere com $n manual falhou: %d, %v", n, err)
+	}
+	if n, err := repo.ForceDeleteWhere(repo.NewQuery(ctx).OnlyTrashed().And("deleted_at < ?", fixedNow)); err != nil || n != 3 {
+		t.Fatalf("ForceDeleteWhere falhou: %d, %v", n, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestSoftDelete_OnlyTrashedWithoutColumn(t *testing.T) {
+	repo, _, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	if _, err := repo.NewQuery(ctx).OnlyTrashed().Query(); err == nil {
+		t.Error("Esperava erro de OnlyTrashed em entidade sem deleted_at")
+	}
+}
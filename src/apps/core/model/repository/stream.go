@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync/atomic"
+)
+
+// cursorSeq gera nomes únicos para cursores abertos na mesma transação.
+var cursorSeq atomic.Uint64
+
+/*
+Cursor faz Each/Iter lerem o resultado em lotes de batchSize por meio de um
+cursor no servidor (DECLARE/FETCH; no SQLite, páginas LIMIT/OFFSET). O
+cursor roda dentro de uma transação (a do ctx, se houver) e permite usar
+Preload, aplicado a cada lote.
+
+	err := repo.NewQuery(ctx).OrderAsc("id").Cursor(1000).Each(func(u *entities.Usuario) error {
+		return csvWriter.Write(...)
+	})
+*/
+func (qb *QueryBuilder[T, P]) Cursor(batchSize uint64) IQueryBuilder[T, P] {
+	if batchSize == 0 {
+		qb.fail(errors.New("tamanho do lote do cursor deve ser maior que zero"))
+		return qb
+	}
+	qb.batch = batchSize
+	return qb
+}
+
+// Each percorre o resultado linha a linha, sem carregá-lo todo em memória.
+// Um erro retornado por fn interrompe a leitura e é devolvido por Each.
+func (qb *QueryBuilder[T, P]) Each(fn func(*T) error) error {
+	for item, err := range qb.Iter() {
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Iter devolve o resultado como iter.Seq2, escaneando uma linha por vez:
+
+	for u, err := range repo.NewQuery(ctx).Iter() {
+		if err != nil {
+			return err
+		}
+		...
+	}
+
+Sem Cursor, a conexão fica presa até o fim do laço; dentro de uma transação
+não execute outros comandos no corpo do laço nem no AfterFind da entidade
+(use Cursor nesse caso).
+*/
+func (qb *QueryBuilder[T, P]) Iter() iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		if qb.err != nil {
+			yield(nil, qb.err)
+			return
+		}
+		if qb.batch > 0 {
+			qb.iterCursor(yield)
+			return
+		}
+		if len(qb.preload) > 0 {
+			yield(nil, errors.New("Preload em streaming exige Cursor(n)"))
+			return
+		}
+
+		query, args := qb.buildSelectSQL()
+		rows, err := qb.repo.query(qb.ctx, qb.repo.reader(qb.ctx), query, args...)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			dest := new(T)
+			if err := qb.scanEntity(rows, dest); err != nil {
+				yield(nil, err)
+				return
+			}
+			if err := afterFind(qb.ctx, P(dest)); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(dest, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// iterCursor lê o resultado em lotes com DECLARE/FETCH dentro de uma transação.
+func (qb *QueryBuilder[T, P]) iterCursor(yield func(*T, error) bool) {
+	stopped := false
+
+	err := qb.repo.WithTx(qb.ctx, func(tx *Tx) error {
+		fetch, closeCursor, err := qb.openCursor(tx)
+		if err != nil {
+			return err
+		}
+		defer closeCursor()
+
+		for {
+			batch, err := fetch()
+			if err != nil {
+				return err
+			}
+			// As linhas do lote já foram liberadas, então o Preload pode usar a transação
+			if err := qb.loadRelations(tx, batch); err != nil {
+				return err
+			}
+			if err := afterFindAll[T, P](tx, batch); err != nil {
+				return err
+			}
+			for _, item := range batch {
+				if !yield(item, nil) {
+					stopped = true
+					return nil
+				}
+			}
+			if uint64(len(batch)) < qb.batch {
+				return nil
+			}
+		}
+	})
+
+	if err != nil && !stopped {
+		yield(nil, err)
+	}
+}
+
+// openCursor abre o cursor no servidor e devolve a função que lê o próximo
+// lote. Sem cursores no servidor (SQLite), os lotes são páginas
+// LIMIT/OFFSET da consulta, lidas na mesma transação.
+func (qb *QueryBuilder[T, P]) openCursor(tx *Tx) (func() ([]*T, error), func(), error) {
+	query, args := qb.buildSelectSQL()
+
+	if !qb.repo.dialect.ServerCursor() {
+		var offset uint64
+		fetch := func() ([]*T, error) {
+			page := "SELECT * FROM (" + query + ")" + qb.repo.dialect.LimitOffset(qb.batch, offset)
+			batch, err := qb.fetchBatch(tx, page, args...)
+			offset += uint64(len(batch))
+			return batch, err
+		}
+		return fetch, func() {}, nil
+	}
+
+	name := fmt.Sprintf("qb_cursor_%d", cursorSeq.Add(1))
+	if _, err := qb.repo.exec(tx, tx, "DECLARE "+name+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return nil, nil, fmt.Errorf("erro ao abrir cursor: %w", err)
+	}
+	closeCursor := func() {
+		tx.ExecContext(context.WithoutCancel(tx), "CLOSE "+name)
+	}
+
+	fetchSQL := fmt.Sprintf("FETCH FORWARD %d FROM %s", qb.batch, name)
+	fetch := func() ([]*T, error) {
+		return qb.fetchBatch(tx, fetchSQL)
+	}
+	return fetch, closeCursor, nil
+}
+
+// fetchBatch executa um FETCH (ou a página equivalente) e escaneia o lote inteiro.
+func (qb *QueryBuilder[T, P]) fetchBatch(tx *Tx, fetch string, args ...any) ([]*T, error) {
+	rows, err := qb.repo.query(tx, tx, fetch, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := make([]*T, 0, qb.batch)
+	for rows.Next() {
+		dest := new(T)
+		if err := qb.scanEntity(rows, dest); err != nil {
+			return nil, err
+		}
+		batch = append(batch, dest)
+	}
+	return batch, rows.Err()
+}
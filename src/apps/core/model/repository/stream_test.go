@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestEach_StreamsRows(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users" WHERE age > $1`)).
+		WithArgs(18).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).
+			AddRow(1, "Ana", 20).
+			AddRow(2, "Bia", 30))
+
+	var ids []int64
+	err := repo.Where(ctx, "age > ?", 18).Each(func(u *MockUser) error {
+		ids = append(ids, u.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each falhou: %s", err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("IDs inesperados: %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestEach_StopsOnCallbackError(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, age FROM "public"."users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).
+			AddRow(1, "Ana", 20).
+			AddRow(2, "Bia", 30))
+
+	expected := errors.New("falha na exportação")
+	calls := 0
+	err := repo.NewQuery(ctx).Each(func(u *MockUser) error {
+		calls++
+		return expected
+	})
+	if !errors.Is(err, expected) || calls != 1 {
+		t.Errorf("Esperava parar no primeiro erro, obteve %v após %d chamadas", err, calls)
+	}
+}
+
+func TestIter_CursorBatches(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DECLARE qb_cursor_\d+ NO SCROLL CURSOR FOR SELECT id, name, age FROM "public"\."users" ORDER BY id`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FETCH FORWARD 2 FROM qb_cursor_\d+`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).
+			AddRow(1, "Ana", 20).
+			AddRow(2, "Bia", 30))
+	mock.ExpectQuery(`FETCH FORWARD 2 FROM qb_cursor_\d+`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "age"}).
+			AddRow(3, "Caio", 40))
+	mock.ExpectExec(`CLOSE qb_cursor_\d+`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	var ids []int64
+	for u, err := range repo.NewQuery(ctx).OrderAsc("id").Cursor(2).Iter() {
+		if err != nil {
+			t.Fatalf("Iter falhou: %s", err)
+		}
+		ids = append(ids, u.ID)
+	}
+	if len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("IDs inesperados: %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestIter_PreloadRequiresCursor(t *testing.T) {
+	repo, _, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	err := repo.NewQuery(ctx).Preload("Pedidos").Each(func(*MockUser) error { return nil })
+	if err == nil {
+		t.Error("Esperava erro ao usar Preload sem Cursor")
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+)
+
+/*
+columnTag é o mapeamento de um campo para sua coluna, lido da tag `db`:
+
+	ID     int64  `db:"id,pk"`
+	Total  int    `db:"total,readonly"`    // calculada pelo banco: só leitura
+	Status string `db:"status,omitinsert"` // INSERT usa o DEFAULT da coluna
+	Senha  string `db:"senha" json:"-"`    // persistida, mas fora do JSON
+
+Sem a tag `db` vale o nome da tag `json` (entidades antigas), com `pk:"true"`
+marcando a chave primária.
+*/
+type columnTag struct {
+	name       string // "" sem tag, "-" para campos ignorados
+	pk         bool
+	readonly   bool
+	omitInsert bool
+}
+
+// parseColumnTag lê a tag `db` do campo ou, na falta dela, a tag `json`.
+func parseColumnTag(field reflect.StructField) columnTag {
+	jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	tag := columnTag{name: jsonName, pk: field.Tag.Get("pk") == "true"}
+
+	db, ok := field.Tag.Lookup("db")
+	if !ok {
+		return tag
+	}
+
+	name, opts, _ := strings.Cut(db, ",")
+	if name != "" {
+		tag.name = name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		switch strings.TrimSpace(opt) {
+		case "pk":
+			tag.pk = true
+		case "readonly":
+			tag.readonly = true
+		case "omitinsert":
+			tag.omitInsert = true
+		}
+	}
+	return tag
+}
+
+// isColumn informa se a tag aponta para uma coluna.
+func (t columnTag) isColumn() bool {
+	return t.name != "" && t.name != "-"
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"deskapp/src/apps/core/model/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// MockPedido usa a tag db com nomes de JSON diferentes das colunas
+type MockPedido struct {
+	Numero int64  `db:"id,pk" json:"numero"`
+	Nome   string `db:"nome" json:"name,omitempty"`
+	Total  int    `db:"total,readonly" json:"total"`
+	Status string `db:"status,omitinsert" json:"status"`
+	Token  string `db:"token" json:"-"`
+}
+
+func (m *MockPedido) Columns() []string { return []string{"id", "nome", "total", "status", "token"} }
+
+func (m *MockPedido) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.Numero, &m.Nome, &m.Total, &m.Status, &m.Token)
+}
+
+func TestParseColumnTag(t *testing.T) {
+	type sample struct {
+		A int `db:"a,pk,readonly"`
+		B int `db:",omitinsert" json:"b,omitempty"`
+		C int `json:"c" pk:"true"`
+		D int `db:"-" json:"d"`
+		E int
+	}
+	typ := reflect.TypeFor[sample]()
+
+	tests := []struct {
+		field string
+		want  columnTag
+	}{
+		{"A", columnTag{name: "a", pk: true, readonly: true}},
+		{"B", columnTag{name: "b", omitInsert: true}},
+		{"C", columnTag{name: "c", pk: true}},
+		{"D", columnTag{name: "-"}},
+		{"E", columnTag{}},
+	}
+	for _, tt := range tests {
+		field, _ := typ.FieldByName(tt.field)
+		if got := parseColumnTag(field); got != tt.want {
+			t.Errorf("%s: esperava %+v, obteve %+v", tt.field, tt.want, got)
+		}
+	}
+}
+
+func TestDBTag_InsertAndUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockPedido](db, "pedidos", "")
+	ctx := t.Context()
+
+	cols := []string{"id", "nome", "total", "status", "token"}
+	// total (readonly) e status (omitinsert) ficam fora do INSERT
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pedidos" (nome, token) VALUES ($1, $2) RETURNING id, nome, total, status, token`)).
+		WithArgs("Ana", "t0k3n").
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(7, "Ana", 0, "aberto", "t0k3n"))
+	// total (readonly) fica fora do UPDATE; a PK vem da tag db:",pk"
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "pedidos" SET nome = $1, status = $2, token = $3 WHERE id = $4`)).
+		WithArgs("Ana", "pago", "t0k3n", int64(7)).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(7, "Ana", 100, "pago", "t0k3n"))
+
+	pedido := &MockPedido{Nome: "Ana", Total: 999, Status: "ignorado", Token: "t0k3n"}
+	if err := repo.Insert(ctx, pedido); err != nil {
+		t.Fatalf("Insert falhou: %s", err)
+	}
+	pedido.Status = "pago"
+	if err := repo.Update(ctx, pedido); err != nil {
+		t.Fatalf("Update falhou: %s", err)
+	}
+
+	if _, err := repo.UpdateWhere(repo.Where(ctx, "id = ?", 7), map[string]any{"total": 1}); err == nil {
+		t.Error("Esperava erro ao atualizar coluna readonly")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
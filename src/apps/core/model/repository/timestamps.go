@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"deskapp/src/apps/core/model/entities"
+	"reflect"
+	"time"
+)
+
+// Colunas gerenciadas automaticamente quando presentes em Columns().
+const (
+	ColumnCreatedAt = "created_at"
+	ColumnUpdatedAt = "updated_at"
+	ColumnDeletedAt = "deleted_at"
+)
+
+// nowFunc é o relógio usado nos timestamps (substituível nos testes).
+var nowFunc = time.Now
+
+// touchTimestamps preenche updated_at com o horário atual e, na criação,
+// também created_at (se ainda estiver vazio).
+func touchTimestamps(entity entities.Entity, creating bool) time.Time {
+	meta := metaOf(entity)
+	now := nowFunc()
+	if creating {
+		if f, ok := meta.field(entity, ColumnCreatedAt); ok && f.IsZero() {
+			setTimeField(f, now)
+		}
+	}
+	if f, ok := meta.field(entity, ColumnUpdatedAt); ok {
+		setTimeField(f, now)
+	}
+	return now
+}
+
+// setTimeField grava t (ou zera, se t for zero) em campos time.Time,
+// *time.Time e sql.NullTime. Outros tipos são ignorados e ficam a cargo do banco.
+func setTimeField(f reflect.Value, t time.Time) {
+	switch f.Interface().(type) {
+	case time.Time:
+		f.Set(reflect.ValueOf(t))
+	case *time.Time:
+		if t.IsZero() {
+			f.Set(reflect.Zero(f.Type()))
+		} else {
+			f.Set(reflect.ValueOf(&t))
+		}
+	case sql.NullTime:
+		f.Set(reflect.ValueOf(sql.NullTime{Time: t, Valid: !t.IsZero()}))
+	}
+}
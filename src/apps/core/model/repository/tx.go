@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Executor é o contrato comum entre *sql.DB e *sql.Tx usado pelo repositório
+// e pelo QueryBuilder para executar comandos.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// TxFunc é a unidade de trabalho executada dentro de uma transação.
+type TxFunc func(tx *Tx) error
+
+// Tx representa uma transação em andamento.
+// Ela implementa context.Context e Executor, então pode ser passada
+// diretamente como ctx para qualquer repositório, que passará a
+// executar seus comandos dentro da mesma transação.
+type Tx struct {
+	context.Context
+	tx  *sql.Tx
+	db  *sql.DB // banco em que a transação foi aberta
+	seq *int
+}
+
+type txKey struct{}
+
+// ExecContext executa um comando dentro da transação.
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+// QueryContext executa uma consulta dentro da transação.
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext executa uma consulta de uma linha dentro da transação.
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+// PrepareContext prepara um comando dentro da transação (usado pelo COPY).
+func (t *Tx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.tx.PrepareContext(ctx, query)
+}
+
+// Value devolve a própria transação para a chave interna, permitindo que
+// contextos derivados de um *Tx continuem enxergando a transação.
+func (t *Tx) Value(key any) any {
+	if _, ok := key.(txKey); ok {
+		return t
+	}
+	return t.Context.Value(key)
+}
+
+// TxFromContext retorna a transação carregada pelo contexto, se houver.
+func TxFromContext(ctx context.Context) (*Tx, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tx, ok := ctx.Value(txKey{}).(*Tx)
+	return tx, ok
+}
+
+// txFor retorna a transação do ctx aberta em db. Transações de outros
+// bancos (conexões nomeadas) são ignoradas, mas uma do mesmo banco aberta
+// antes delas continua valendo.
+func txFor(ctx context.Context, db *sql.DB) (*Tx, bool) {
+	for {
+		tx, ok := TxFromContext(ctx)
+		if !ok || tx.db == db {
+			return tx, ok
+		}
+		ctx = tx.Context
+	}
+}
+
+// WithTx executa fn dentro de uma transação aberta em db.
+// Se ctx já carregar uma transação de db, fn roda em um SAVEPOINT dela;
+// a transação de outro banco não é reaproveitada.
+// Retornar erro (ou entrar em pânico) dentro de fn desfaz as alterações;
+// o pânico é repassado depois do rollback.
+func WithTx(ctx context.Context, db *sql.DB, fn TxFunc) error {
+	if parent, ok := txFor(ctx, db); ok {
+		return parent.savepoint(ctx, fn)
+	}
+
+	if db == nil {
+		return fmt.Errorf("conexão com o banco não inicializada")
+	}
+
+	sqlTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+
+	seq := 0
+	tx := &Tx{Context: ctx, tx: sqlTx, db: db, seq: &seq}
+
+	var success bool
+	defer func() {
+		if success {
+			return
+		}
+		// Cobre tanto o retorno com erro quanto o pânico
+		sqlTx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := sqlTx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	success = true
+	return nil
+}
+
+// savepoint executa fn em uma transação aninhada usando SAVEPOINT.
+func (t *Tx) savepoint(ctx context.Context, fn TxFunc) error {
+	*t.seq++
+	name := fmt.Sprintf("sp_%d", *t.seq)
+
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("erro ao criar savepoint: %w", err)
+	}
+
+	nested := &Tx{Context: ctx, tx: t.tx, db: t.db, seq: t.seq}
+
+	var success bool
+	defer func() {
+		if success {
+			return
+		}
+		t.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name)
+	}()
+
+	if err := fn(nested); err != nil {
+		return err
+	}
+
+	if _, err := t.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("erro ao liberar savepoint: %w", err)
+	}
+	success = true
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWithTx_Commit(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."users" WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.WithTx(ctx, func(tx *Tx) error {
+		return repo.Delete(tx, &MockUser{ID: 1})
+	})
+	if err != nil {
+		t.Errorf("WithTx falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestWithTx_RollbackOnError(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	expected := errors.New("falha na regra de negócio")
+	err := repo.WithTx(ctx, func(tx *Tx) error {
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Errorf("Esperava %v, obteve %v", expected, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestWithTx_RollbackOnPanic(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Esperava que o pânico fosse repassado")
+			}
+		}()
+		repo.WithTx(ctx, func(tx *Tx) error {
+			panic("boom")
+		})
+	}()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestWithTx_NestedSavepoint(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.WithTx(ctx, func(tx *Tx) error {
+		// A falha do primeiro bloco aninhado não deve derrubar a transação externa
+		if err := repo.WithTx(tx, func(*Tx) error { return sql.ErrNoRows }); err == nil {
+			t.Error("Esperava erro do savepoint")
+		}
+		return repo.WithTx(tx, func(*Tx) error { return nil })
+	})
+	if err != nil {
+		t.Errorf("WithTx falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestWithTx_OtherDatabase(t *testing.T) {
+	repo, mock, ctx := setup(t)
+	defer repo.GetDB().Close()
+	otherDB, other, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer otherDB.Close()
+	otherRepo := NewBaseRepository[MockUser](otherDB, "users", "public")
+
+	// Cada banco abre a própria transação, sem SAVEPOINT na do outro; de
+	// volta ao primeiro banco, a transação externa continua valendo
+	mock.ExpectBegin()
+	other.ExpectBegin()
+	other.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."users" WHERE id = $1`)).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "public"."users" WHERE id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	other.ExpectCommit()
+	mock.ExpectCommit()
+
+	err = repo.WithTx(ctx, func(tx *Tx) error {
+		return otherRepo.WithTx(tx, func(otherTx *Tx) error {
+			if err := otherRepo.Delete(otherTx, &MockUser{ID: 2}); err != nil {
+				return err
+			}
+			return repo.Delete(otherTx, &MockUser{ID: 1})
+		})
+	})
+	if err != nil {
+		t.Errorf("WithTx falhou: %s", err)
+	}
+
+	for _, m := range []sqlmock.Sqlmock{mock, other} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+		}
+	}
+}
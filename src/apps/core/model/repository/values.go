@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// returningClause monta " RETURNING col1, col2, ..." na ordem de Columns(),
+// para que o resultado possa ser lido diretamente pelo ScanRow da entidade.
+func returningClause(cols []string) string {
+	return " RETURNING " + strings.Join(cols, ", ")
+}
+
+// isZeroValue informa se v é o valor zero do seu tipo (0, "", nil...).
+func isZeroValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.ValueOf(v).IsZero()
+}
+
+// isUnsetValue informa se v representa "sem valor" para o INSERT:
+// nil, ponteiro nulo, driver.Valuer que resolve para NULL ou time.Time zerado.
+// Omitir essas colunas deixa o banco aplicar o DEFAULT (ou NULL, se não houver).
+func isUnsetValue(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return true
+	}
+
+	switch val := v.(type) {
+	case time.Time:
+		return val.IsZero()
+	case driver.Valuer:
+		dv, err := val.Value()
+		return err == nil && dv == nil
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"deskapp/src/apps/core/model/entities"
+)
+
+// ColumnVersion é a coluna de lock otimista usada por padrão.
+const ColumnVersion = "version"
+
+// resolveVersionColumn descobre a coluna de versão: método VersionColumn()
+// ou a coluna "version", quando presente em Columns().
+func resolveVersionColumn(entity entities.Entity, m *entityMeta) string {
+	if v, ok := entity.(entities.Versioned); ok {
+		return v.VersionColumn()
+	}
+	if m.hasColumn(ColumnVersion) {
+		return ColumnVersion
+	}
+	return ""
+}
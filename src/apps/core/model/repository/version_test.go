@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"deskapp/src/apps/core/model/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// MockConta usa a coluna padrão "version"
+type MockConta struct {
+	ID      int64   `json:"id"`
+	Saldo   float64 `json:"saldo"`
+	Version int     `json:"version"`
+}
+
+func (m *MockConta) Columns() []string { return []string{"id", "saldo", "version"} }
+
+func (m *MockConta) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.Saldo, &m.Version)
+}
+
+// MockNota declara outra coluna de versão
+type MockNota struct {
+	ID      int64  `json:"id"`
+	Texto   string `json:"texto"`
+	Revisao int    `json:"revisao"`
+}
+
+func (m *MockNota) Columns() []string     { return []string{"id", "texto", "revisao"} }
+func (m *MockNota) VersionColumn() string { return "revisao" }
+
+func (m *MockNota) ScanRow(row entities.DBScanner) error {
+	return row.Scan(&m.ID, &m.Texto, &m.Revisao)
+}
+
+func TestUpdate_OptimisticLock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockConta](db, "contas", "public")
+
+	expectedSQL := `UPDATE "public"."contas" SET saldo = $1, version = version + 1 WHERE id = $2 AND version = $3 RETURNING id, saldo, version`
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WithArgs(150.0, int64(1), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "saldo", "version"}).AddRow(1, 150.0, 4))
+	mock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
+		WithArgs(90.0, int64(1), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "saldo", "version"}))
+
+	conta := &MockConta{ID: 1, Saldo: 150, Version: 3}
+	if err := repo.Update(t.Context(), conta); err != nil {
+		t.Fatalf("Update falhou: %s", err)
+	}
+	if conta.Version != 4 {
+		t.Errorf("Esperava versão 4, obteve %d", conta.Version)
+	}
+
+	// Segunda cópia, lida antes da gravação acima
+	obsoleta := &MockConta{ID: 1, Saldo: 90, Version: 3}
+	err = repo.Update(t.Context(), obsoleta)
+	if !errors.Is(err, ErrStale) || !errors.Is(err, ErrStaleEntity) {
+		t.Errorf("Esperava ErrStale/ErrStaleEntity, obteve %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
+
+func TestUpdate_CustomVersionColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("falha ao criar sqlmock: %s", err)
+	}
+	defer db.Close()
+	repo := NewBaseRepository[MockNota](db, "notas", "")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "notas" SET texto = $1, revisao = revisao + 1 WHERE id = $2 AND revisao = $3`)).
+		WithArgs("oi", int64(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "texto", "revisao"}).AddRow(7, "oi", 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "notas" SET texto = $2, revisao = revisao + 1 WHERE id = $1`)).
+		WithArgs(int64(7), "x").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Update(t.Context(), &MockNota{ID: 7, Texto: "oi", Revisao: 1}); err != nil {
+		t.Fatalf("Update falhou: %s", err)
+	}
+	if _, err := repo.UpdateWhere(repo.Where(t.Context(), "id = ?", int64(7)), map[string]any{"texto": "x"}); err != nil {
+		t.Fatalf("UpdateWhere falhou: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Expectativas do SQLMock não atendidas: %s", err)
+	}
+}
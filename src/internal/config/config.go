@@ -3,7 +3,9 @@ package config
 import (
 	"deskapp/src/internal/utils"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -11,20 +13,111 @@ type Config struct {
 	Port string
 	mode string
 	server string
+	// Databases são as conexões nomeadas (nome -> DSN), lidas de
+	// DATABASE_URL_<NOME>. Ex: DATABASE_URL_REPLICA -> "replica".
+	// O primary continua em DBDSN (DATABASE_URL).
+	Databases map[string]string
+	// DBPool são os limites do pool de conexões (DB_MAX_OPEN_CONNS, ...).
+	DBPool DBPool
+	// DBRetry controla as novas tentativas de conexão e a verificação de saúde.
+	DBRetry DBRetry
+	// SlowQuery é o tempo a partir do qual uma consulta é registrada como
+	// lenta (DB_SLOW_QUERY, padrão 200ms; 0 desliga).
+	SlowQuery time.Duration
+	// AutoMigrate aplica as migrações pendentes na inicialização
+	// (DB_AUTO_MIGRATE=true). Desligado por padrão.
+	AutoMigrate bool
 }
 
+// DBPool configura o pool do database/sql. Zero em uma duração significa
+// sem limite, como no database/sql.
+type DBPool struct {
+	MaxOpenConns    int           // DB_MAX_OPEN_CONNS (padrão 20)
+	MaxIdleConns    int           // DB_MAX_IDLE_CONNS (padrão 2)
+	ConnMaxLifetime time.Duration // DB_CONN_MAX_LIFETIME, ex: "30m"
+	ConnMaxIdleTime time.Duration // DB_CONN_MAX_IDLE_TIME, ex: "5m"
+}
+
+// DBRetry configura o backoff exponencial das reconexões: a espera começa
+// em Initial e dobra a cada falha até Max. Com o banco online, a saúde é
+// verificada a cada HealthInterval.
+type DBRetry struct {
+	Initial        time.Duration // DB_RETRY_INITIAL (padrão 1s)
+	Max            time.Duration // DB_RETRY_MAX (padrão 30s)
+	HealthInterval time.Duration // DB_HEALTH_INTERVAL (padrão 15s)
+}
+
+// databaseEnvPrefix é o prefixo das variáveis de conexões nomeadas.
+const databaseEnvPrefix = "DATABASE_URL_"
+
 func NewConfig() *Config {
 	cfg := &Config{
 		DBDSN: os.Getenv("DATABASE_URL"),
 		Port: os.Getenv("PORT"),
 		mode: os.Getenv("MODE"),
 		server: os.Getenv("SERVER"),
+		Databases: namedDatabases(os.Environ()),
+		DBPool: DBPool{
+			MaxOpenConns:    envInt("DB_MAX_OPEN_CONNS", 20),
+			MaxIdleConns:    envInt("DB_MAX_IDLE_CONNS", 2),
+			ConnMaxLifetime: envDuration("DB_CONN_MAX_LIFETIME", 0),
+			ConnMaxIdleTime: envDuration("DB_CONN_MAX_IDLE_TIME", 0),
+		},
+		DBRetry: DBRetry{
+			Initial:        envDuration("DB_RETRY_INITIAL", time.Second),
+			Max:            envDuration("DB_RETRY_MAX", 30*time.Second),
+			HealthInterval: envDuration("DB_HEALTH_INTERVAL", 15*time.Second),
+		},
+		SlowQuery: envDuration("DB_SLOW_QUERY", 200*time.Millisecond),
+		AutoMigrate: strings.ToUpper(os.Getenv("DB_AUTO_MIGRATE")) == "TRUE",
 	}
 	cfg.Setup()
 
 	return cfg
 }
 
+// namedDatabases extrai as conexões DATABASE_URL_<NOME>=dsn do ambiente.
+func namedDatabases(environ []string) map[string]string {
+	dbs := make(map[string]string)
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(key, databaseEnvPrefix)
+		if !ok || name == "" || value == "" {
+			continue
+		}
+		dbs[strings.ToLower(name)] = value
+	}
+	return dbs
+}
+
+// envInt lê um inteiro não negativo do ambiente; ausente ou inválido usa def.
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		utils.NewLogger().Warningf("%s inválido (%q), usando %d", key, value, def)
+		return def
+	}
+	return n
+}
+
+// envDuration lê uma duração ("500ms", "30s", "5m") do ambiente; ausente
+// ou inválida usa def.
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		utils.NewLogger().Warningf("%s inválido (%q), usando %s", key, value, def)
+		return def
+	}
+	return d
+}
 
 func (c *Config) Setup() {
 	logger := utils.NewLogger()
@@ -2,41 +2,281 @@ package database
 
 import (
 	"database/sql"
+	"deskapp/src/internal/config"
 	"deskapp/src/internal/utils"
+	"fmt"
 	_ "github.com/lib/pq"
+	"sort"
+	"strings"
 	"sync"
+
+	_ "modernc.org/sqlite"
+)
+
+// Nomes das conexões padrão. Outras conexões usam o nome configurado
+// (ver config.Config.Databases).
+const (
+	Primary = "primary"
+	Replica = "replica"
 )
 
 var (
 	dbInstance *sql.DB
 	once       sync.Once
-	logger     *utils.Logger
+	primaryMu  sync.Mutex // protege dbInstance e once
+	logger     = utils.NewLogger()
+
+	// pool são os limites aplicados às próximas conexões abertas (SetPool)
+	pool   = config.DBPool{MaxOpenConns: 20, MaxIdleConns: 2}
+	poolMu sync.RWMutex
+
+	// registry guarda as conexões abertas por nome
+	registry   = make(map[string]*sql.DB)
+	registryMu sync.RWMutex
 )
 
+/*
+InitDB cria o pool do primary e testa a conexão. Se o banco ainda não
+estiver no ar, o pool é criado mesmo assim e devolvido junto do erro: o
+status fica offline (ver Health) e Watch continua tentando em segundo
+plano, então GetDB nunca devolve nil depois do InitDB. Chamadas seguintes
+devolvem o mesmo pool. Ele nunca é trocado: quando o banco volta, o
+database/sql refaz as conexões sozinho e quem guardou o *sql.DB (apps,
+repositórios) continua funcionando. Sem DSN, ou se o pool nem chega a ser
+criado, nada fica registrado e o próximo InitDB tenta de novo.
+*/
 func InitDB(dsn string) (*sql.DB, error) {
-	logger = utils.NewLogger()
+	primaryMu.Lock()
+	defer primaryMu.Unlock()
+
+	if dbInstance == nil && dsn == "" {
+		return nil, ErrNoDSN
+	}
 	var err error
 	once.Do(func() {
-		dbInstance, err = sql.Open("postgres", dsn)
+		var db *sql.DB
+		db, err = newPool(dsn)
 		if err != nil {
 			return
 		}
+		dbInstance = db
+		register(Primary, db)
+		setConnecting()
 
-		err = dbInstance.Ping()
-		if err != nil {
-			dbInstance.Close()
-			dbInstance = nil
+		if err = db.Ping(); err != nil {
+			setOffline(err)
 			return
 		}
-		dbInstance.SetMaxOpenConns(20)
-		logger.Infof("Database connection pool initialized successfully: %s", dsn)
+		setOnline()
+		logger.Infof("Database connection pool initialized successfully")
 	})
+	if dbInstance == nil {
+		once = sync.Once{}
+		return nil, err
+	}
+	if err == nil && !Health().Online() {
+		err = ErrOffline
+	}
 	return dbInstance, err
 }
 
 func GetDB() *sql.DB {
+	primaryMu.Lock()
+	defer primaryMu.Unlock()
 	if dbInstance == nil {
 		panic("Database connection has not been initialized. Call InitDB() first.")
 	}
 	return dbInstance
 }
+
+// SetPool define os limites do pool (ver config.DBPool) usados pelas
+// conexões abertas a partir de então. Chame antes do InitDB.
+func SetPool(p config.DBPool) {
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	pool = p
+}
+
+// Esquema do DATABASE_URL que seleciona o SQLite embutido: sqlite://arquivo.db
+const sqliteScheme = "sqlite://"
+
+// IsSQLite informa se o DSN aponta para um arquivo SQLite.
+func IsSQLite(dsn string) bool {
+	return strings.HasPrefix(dsn, sqliteScheme)
+}
+
+/*
+Driver devolve o driver do database/sql e o DSN que ele espera:
+
+	sqlite://dados/app.db      -> "sqlite",   "dados/app.db?_pragma=foreign_keys(1)&..."
+	sqlite:///var/app/app.db   -> "sqlite",   "/var/app/app.db?..."
+	postgresql://user:pw@host  -> "postgres", sem alterações
+
+No SQLite as chaves estrangeiras são ligadas, o journal fica em WAL (as
+leituras não bloqueiam o escritor) e a espera por lock é de 5s, a menos
+que o DSN já traga seus próprios _pragma. As transações pegam o lock de
+escrita no BEGIN (_txlock=immediate), então uma escrita concorrente espera
+o busy_timeout em vez de falhar ao promover o lock.
+*/
+func Driver(dsn string) (driverName, source string) {
+	if !IsSQLite(dsn) {
+		return "postgres", dsn
+	}
+	source = strings.TrimPrefix(dsn, sqliteScheme)
+	if strings.Contains(source, "_pragma=") {
+		return "sqlite", source
+	}
+	sep := "?"
+	if strings.Contains(source, "?") {
+		sep = "&"
+	}
+	return "sqlite", source + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+}
+
+// sqliteMaxConns é o máximo de conexões SQLite: poucas, já que só há um
+// escritor por vez, e uma só para um banco em memória, onde cada conexão
+// teria o seu próprio banco.
+func sqliteMaxConns(source string, configured int) int {
+	if strings.HasPrefix(source, ":memory:") || strings.Contains(source, "mode=memory") {
+		return 1
+	}
+	const maxConns = 4
+	if configured <= 0 || configured > maxConns {
+		return maxConns
+	}
+	return configured
+}
+
+// newPool cria o pool de conexões com o Postgres ou o SQLite, sem conectar.
+func newPool(dsn string) (*sql.DB, error) {
+	driverName, source := Driver(dsn)
+	db, err := sql.Open(driverName, source)
+	if err != nil {
+		return nil, err
+	}
+
+	poolMu.RLock()
+	p := pool
+	poolMu.RUnlock()
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	if driverName == "sqlite" {
+		// Mais de uma conexão: consultas feitas com outras linhas abertas
+		// (callback do Each, repositório fora do WithTx) não ficam esperando
+		// a única conexão para sempre
+		db.SetMaxOpenConns(sqliteMaxConns(source, p.MaxOpenConns))
+	}
+	return db, nil
+}
+
+// open abre e valida um pool de conexões.
+func open(dsn string) (*sql.DB, error) {
+	db, err := newPool(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func register(name string, db *sql.DB) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = db
+}
+
+// Open abre a conexão nomeada e a registra. Se o nome já estiver
+// registrado, devolve a conexão existente. O nome Primary equivale a InitDB.
+func Open(name, dsn string) (*sql.DB, error) {
+	if name == Primary {
+		return InitDB(dsn)
+	}
+	if db, ok := Get(name); ok {
+		return db, nil
+	}
+
+	db, err := open(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar com o banco %q: %w", name, err)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	// Outra goroutine pode ter aberto a mesma conexão nesse meio tempo
+	if existing, ok := registry[name]; ok {
+		db.Close()
+		return existing, nil
+	}
+	registry[name] = db
+	logger.Infof("Database connection %q initialized successfully", name)
+	return db, nil
+}
+
+// OpenAll abre todas as conexões configuradas (nome -> DSN). Falhas são
+// registradas no log e devolvidas juntas, sem impedir as demais conexões.
+func OpenAll(dsns map[string]string) error {
+	names := make([]string, 0, len(dsns))
+	for name := range dsns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if _, err := Open(name, dsns[name]); err != nil {
+			logger.Warningf("%v", err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d conexão(ões) falharam: %v", len(errs), errs)
+	}
+	return nil
+}
+
+// Get devolve a conexão registrada com o nome informado.
+func Get(name string) (*sql.DB, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	db, ok := registry[name]
+	return db, ok
+}
+
+// MustGet devolve a conexão nomeada ou entra em pânico, como GetDB.
+func MustGet(name string) *sql.DB {
+	db, ok := Get(name)
+	if !ok {
+		panic(fmt.Sprintf("Database connection %q has not been initialized.", name))
+	}
+	return db
+}
+
+// GetReplica devolve a réplica de leitura ou, se não houver, o primary.
+func GetReplica() *sql.DB {
+	if db, ok := Get(Replica); ok {
+		return db
+	}
+	return GetDB()
+}
+
+// CloseAll fecha todas as conexões registradas (inclusive o primary).
+func CloseAll() {
+	primaryMu.Lock()
+	defer primaryMu.Unlock()
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for name, db := range registry {
+		if err := db.Close(); err != nil {
+			logger.Warningf("erro ao fechar conexão %q: %v", name, err)
+		}
+		delete(registry, name)
+	}
+	dbInstance = nil
+	once = sync.Once{}
+	setDisconnected()
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLitePool_QueriesWhileRowsOpen(t *testing.T) {
+	db, err := open("sqlite://" + filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	var mode string
+	if err := db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil || mode != "wal" {
+		t.Fatalf("Esperava journal_mode wal, obteve %q (%v)", mode, err)
+	}
+	if _, err := db.Exec(`CREATE TABLE itens (id INTEGER); INSERT INTO itens VALUES (1), (2); CREATE TABLE copia (id INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Com uma conexão só, as consultas dentro do laço esperariam para sempre
+	done := make(chan error, 1)
+	go func() {
+		rows, err := db.Query(`SELECT id FROM itens`)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				done <- err
+				return
+			}
+			if _, err := db.Exec(`INSERT INTO copia VALUES (?1)`, id); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- rows.Err()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Consulta com linhas abertas falhou: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Consulta com linhas abertas travou")
+	}
+
+	var copied int
+	db.QueryRow(`SELECT COUNT(*) FROM copia`).Scan(&copied)
+	if copied != 2 {
+		t.Errorf("Esperava 2 linhas copiadas, obteve %d", copied)
+	}
+}
+
+func TestSQLiteMaxConns(t *testing.T) {
+	cases := []struct {
+		source     string
+		configured int
+		want       int
+	}{
+		{":memory:?_pragma=foreign_keys(1)", 20, 1},
+		{"file:x?mode=memory", 20, 1},
+		{"app.db", 20, 4},
+		{"app.db", 0, 4},
+		{"app.db", 2, 2},
+	}
+	for _, c := range cases {
+		if got := sqliteMaxConns(c.source, c.configured); got != c.want {
+			t.Errorf("sqliteMaxConns(%q, %d) = %d, esperava %d", c.source, c.configured, got, c.want)
+		}
+	}
+}
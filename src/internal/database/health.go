@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"deskapp/src/internal/config"
+	"errors"
+	"sync"
+	"time"
+)
+
+// State é o estado da conexão com o banco primary.
+type State string
+
+const (
+	StateDisconnected State = "disconnected" // InitDB ainda não foi chamado
+	StateConnecting   State = "connecting"   // primeira tentativa em andamento
+	StateOnline       State = "online"
+	StateOffline      State = "offline" // Watch segue tentando reconectar
+)
+
+// ErrOffline indica que o banco primary não está acessível no momento.
+var ErrOffline = errors.New("banco de dados offline")
+
+// ErrNoDSN indica que o InitDB foi chamado sem DATABASE_URL.
+var ErrNoDSN = errors.New("DATABASE_URL não configurado")
+
+// HealthStatus é a foto da saúde do primary, para as páginas mostrarem
+// "banco offline" em vez de falhar:
+//
+//	if h := database.Health(); !h.Online() {
+//		// h.State, h.Err, h.Since
+//	}
+type HealthStatus struct {
+	State     State
+	Err       error     // última falha, quando offline
+	Since     time.Time // quando entrou no estado atual
+	Attempts  int       // tentativas de reconexão desde a queda
+	CheckedAt time.Time // última verificação
+}
+
+// Online informa se o banco respondeu à última verificação.
+func (h HealthStatus) Online() bool {
+	return h.State == StateOnline
+}
+
+var (
+	health   = HealthStatus{State: StateDisconnected}
+	healthMu sync.RWMutex
+)
+
+// Health devolve o estado atual do banco primary.
+func Health() HealthStatus {
+	healthMu.RLock()
+	defer healthMu.RUnlock()
+	return health
+}
+
+func setDisconnected() {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	health = HealthStatus{State: StateDisconnected, Since: time.Now()}
+}
+
+func setConnecting() {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	now := time.Now()
+	health = HealthStatus{State: StateConnecting, Since: now, CheckedAt: now}
+}
+
+func setOnline() {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	now := time.Now()
+	if health.State != StateOnline {
+		health = HealthStatus{State: StateOnline, Since: now}
+	}
+	health.CheckedAt = now
+}
+
+// setOffline registra a falha e devolve quantas tentativas já falharam.
+func setOffline(err error) int {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	now := time.Now()
+	if health.State != StateOffline {
+		health = HealthStatus{State: StateOffline, Since: now}
+	}
+	health.Err = err
+	health.Attempts++
+	health.CheckedAt = now
+	return health.Attempts
+}
+
+/*
+Watch verifica o primary periodicamente até ctx ser cancelado. Com o banco
+online, faz um Ping a cada retry.HealthInterval; quando ele cai (ou ainda
+não subiu), tenta de novo com backoff exponencial, de retry.Initial até
+retry.Max. O database/sql refaz as conexões sozinho, então basta o Ping
+voltar a responder para o status ficar online.
+
+	go database.Watch(ctx, cfg.DBRetry)
+*/
+func Watch(ctx context.Context, retry config.DBRetry) {
+	if retry.HealthInterval <= 0 {
+		retry.HealthInterval = 15 * time.Second
+	}
+	delay := retry.HealthInterval
+	if h := Health(); !h.Online() {
+		delay = backoff(retry, h.Attempts)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		timer.Reset(check(ctx, retry))
+	}
+}
+
+// check faz um Ping no primary, atualiza o status e devolve a espera até a
+// próxima verificação.
+func check(ctx context.Context, retry config.DBRetry) time.Duration {
+	primaryMu.Lock()
+	db := dbInstance
+	primaryMu.Unlock()
+	if db == nil {
+		return retry.HealthInterval
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	wasOnline := Health().Online()
+	if err := db.PingContext(pingCtx); err != nil {
+		attempts := setOffline(err)
+		wait := backoff(retry, attempts)
+		if wasOnline {
+			logger.Warningf("Banco de dados offline: %v", err)
+		}
+		logger.Warningf("Sem conexão com o banco (tentativa %d), nova tentativa em %s", attempts, wait)
+		return wait
+	}
+
+	setOnline()
+	if !wasOnline {
+		logger.Infof("Conexão com o banco de dados estabelecida")
+	}
+	return retry.HealthInterval
+}
+
+// backoff calcula a espera após attempts falhas: Initial, 2x, 4x... até Max.
+func backoff(retry config.DBRetry, attempts int) time.Duration {
+	wait := retry.Initial
+	if wait <= 0 {
+		wait = time.Second
+	}
+	for i := 1; i < attempts && wait < retry.Max; i++ {
+		wait *= 2
+	}
+	if retry.Max > 0 {
+		wait = min(wait, retry.Max)
+	}
+	return wait
+}
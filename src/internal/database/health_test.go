@@ -0,0 +1,69 @@
+package database
+
+import (
+	"deskapp/src/internal/config"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	retry := config.DBRetry{Initial: time.Second, Max: 5 * time.Second}
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempts, w := range want {
+		if got := backoff(retry, attempts); got != w {
+			t.Errorf("backoff(%d) = %s, esperava %s", attempts, got, w)
+		}
+	}
+}
+
+func TestInitDB_OfflineThenOnline(t *testing.T) {
+	t.Cleanup(CloseAll)
+
+	// Porta fechada: o pool é criado, mas o banco fica offline
+	db, err := InitDB("postgres://user:pw@127.0.0.1:1/db?sslmode=disable&connect_timeout=1")
+	if err == nil || db == nil {
+		t.Fatalf("Esperava pool criado com erro, obteve db=%v err=%v", db, err)
+	}
+	if h := Health(); h.State != StateOffline || h.Attempts != 1 {
+		t.Fatalf("Esperava offline após a primeira tentativa, obteve %+v", h)
+	}
+	if GetDB() != db {
+		t.Fatal("GetDB deveria devolver o pool mesmo offline")
+	}
+	if _, err := InitDB("sqlite://:memory:"); err != ErrOffline {
+		t.Fatalf("InitDB repetido deveria manter o pool e devolver ErrOffline, obteve %v", err)
+	}
+
+	// CloseAll libera o InitDB para criar outro pool
+	CloseAll()
+	db2, err := InitDB("sqlite://:memory:")
+	if err != nil || db2 == db {
+		t.Fatalf("InitDB após CloseAll: db2=%v err=%v", db2, err)
+	}
+	if !Health().Online() {
+		t.Fatalf("Esperava online, obteve %+v", Health())
+	}
+}
+
+func TestInitDB_NoDSNThenRetry(t *testing.T) {
+	t.Cleanup(CloseAll)
+
+	// Sem DSN nada é criado: Watch não tem o que pingar e o once não é gasto
+	if db, err := InitDB(""); err != ErrNoDSN || db != nil {
+		t.Fatalf("Esperava ErrNoDSN sem pool, obteve db=%v err=%v", db, err)
+	}
+	if h := Health(); h.State != StateDisconnected {
+		t.Fatalf("Esperava disconnected, obteve %+v", h)
+	}
+	if wait := check(t.Context(), config.DBRetry{HealthInterval: time.Minute}); wait != time.Minute {
+		t.Errorf("check sem pool deveria só esperar o intervalo, obteve %s", wait)
+	}
+
+	db, err := InitDB("sqlite://:memory:")
+	if err != nil || db == nil {
+		t.Fatalf("InitDB depois da falha: db=%v err=%v", db, err)
+	}
+	if GetDB() != db || !Health().Online() {
+		t.Fatalf("Esperava o pool novo online, obteve %+v", Health())
+	}
+}
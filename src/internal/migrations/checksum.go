@@ -0,0 +1,263 @@
+package migrations
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DDL da tabela applied_migrations: uma linha por migração aplicada, com o
+// checksum do .up.sql executado. É a mesma nos dois bancos.
+const appliedMigrationsDDL = `
+            CREATE TABLE applied_migrations (
+                version BIGINT PRIMARY KEY,
+                migration_name VARCHAR(255) NOT NULL,
+                checksum VARCHAR(64) NOT NULL,
+                applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                backfilled BOOLEAN NOT NULL DEFAULT false
+            )
+        `
+
+// Tipos de divergência entre as migrações aplicadas e os arquivos.
+const (
+	DriftEdited  = "edited"  // o .up.sql mudou depois de aplicado
+	DriftMissing = "missing" // aplicada, mas o arquivo não existe mais
+	DriftUnknown = "unknown" // não aplicada, mas mais antiga que a última aplicada
+)
+
+// Drift é uma migração cujo arquivo não confere com o que foi aplicado.
+type Drift struct {
+	Version uint
+	Name    string
+	Kind    string // DriftEdited, DriftMissing ou DriftUnknown
+}
+
+// checksum é o sha256 do arquivo, ignorando a diferença entre \r\n e \n
+// para um checkout no Windows não parecer uma edição.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// tableExists informa se a tabela name já foi criada.
+func tableExists(q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}, isSQLite bool, name string) (bool, error) {
+	query := `SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = $1)`
+	if isSQLite {
+		query = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)`
+	}
+	var exists bool
+	err := q.QueryRowContext(context.Background(), query, name).Scan(&exists)
+	return exists, err
+}
+
+/*
+ensureAppliedTable cria applied_migrations na transação da migração. Num
+banco migrado antes dela existir, as versões aplicadas segundo
+migration_logs (ver appliedFromLogs) entram com o checksum dos arquivos
+atuais (backfilled), que passam a ser a referência do Verify. Uma versão
+menor que schema_migrations sem log fica pendente.
+*/
+func (mm *MigrationManager) ensureAppliedTable(tx *sql.Tx) error {
+	exists, err := tableExists(tx, mm.sqlite, "applied_migrations")
+	if err != nil || exists {
+		return err
+	}
+	applied, err := mm.appliedFromLogs(tx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("📋 Criando tabela applied_migrations...")
+	if _, err := tx.Exec(appliedMigrationsDDL); err != nil {
+		return fmt.Errorf("failed to create applied_migrations table: %v", err)
+	}
+	for version, rec := range applied {
+		if _, _, err := mm.readMigrationFile(version, "up"); err == nil {
+			err = mm.recordApplied(tx, version, true)
+		} else {
+			// O arquivo sumiu: fica registrada sem checksum, e o Verify a acusa
+			_, err = tx.Exec(`
+        INSERT INTO applied_migrations (version, migration_name, checksum, backfilled)
+        VALUES ($1, $2, '', true)`, version, rec.name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record migration %d: %v", version, err)
+		}
+	}
+	return nil
+}
+
+// recordApplied grava version em applied_migrations com o checksum do
+// .up.sql atual, substituindo um registro anterior.
+func (mm *MigrationManager) recordApplied(tx *sql.Tx, version uint, backfilled bool) error {
+	_, content, err := mm.readMigrationFile(version, "up")
+	if err != nil {
+		return err
+	}
+	if err := mm.forgetApplied(tx, version); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+        INSERT INTO applied_migrations (version, migration_name, checksum, backfilled)
+        VALUES ($1, $2, $3, $4)`,
+		version, mm.GetMigrationName(version), checksum(content), backfilled)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %v", version, err)
+	}
+	return nil
+}
+
+// forgetApplied remove version de applied_migrations (migração revertida).
+func (mm *MigrationManager) forgetApplied(tx *sql.Tx, version uint) error {
+	_, err := tx.Exec(`DELETE FROM applied_migrations WHERE version = $1`, version)
+	return err
+}
+
+// appliedRecord é uma linha de applied_migrations.
+type appliedRecord struct {
+	name, checksum string
+}
+
+// appliedChecksums lê applied_migrations por versão. Sem a tabela (criada
+// na próxima migração, ver ensureAppliedTable) as versões vêm de
+// migration_logs, sem checksum.
+func (mm *MigrationManager) appliedChecksums() (map[uint]appliedRecord, error) {
+	exists, err := tableExists(mm.conn, mm.sqlite, "applied_migrations")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return mm.appliedFromLogs(mm.conn)
+	}
+
+	rows, err := mm.conn.QueryContext(context.Background(), `SELECT version, migration_name, checksum FROM applied_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	recorded := make(map[uint]appliedRecord)
+	for rows.Next() {
+		var version uint
+		var rec appliedRecord
+		if err := rows.Scan(&version, &rec.name, &rec.checksum); err != nil {
+			return nil, err
+		}
+		recorded[version] = rec
+	}
+	return recorded, rows.Err()
+}
+
+/*
+Verify compara as migrações registradas em applied_migrations com os
+arquivos de source e devolve as divergências em ordem de versão:
+
+  - DriftEdited: o .up.sql foi alterado depois de aplicado;
+  - DriftMissing: a migração foi aplicada mas o arquivo sumiu;
+  - DriftUnknown: o arquivo não foi aplicado mas é mais antigo que a
+    versão atual; Up só o executa com SetAllowOutOfOrder.
+
+Num banco sem applied_migrations as aplicadas vêm de migration_logs e só
+os dois últimos casos são verificados.
+*/
+func (mm *MigrationManager) Verify() ([]Drift, error) {
+	recorded, err := mm.appliedChecksums()
+	if err != nil {
+		return nil, err
+	}
+	applied := slices.Sorted(maps.Keys(recorded))
+	current := mm.currentVersion(applied)
+	sequence, err := mm.GetMigrationSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	for _, version := range sequence {
+		rec, ok := recorded[version]
+		if !ok {
+			if version < current {
+				drifts = append(drifts, Drift{version, mm.GetMigrationName(version), DriftUnknown})
+			}
+			continue
+		}
+		if rec.checksum == "" {
+			continue // ainda sem applied_migrations: não há o que comparar
+		}
+		_, content, err := mm.readMigrationFile(version, "up")
+		if err != nil {
+			return nil, err
+		}
+		if checksum(content) != rec.checksum {
+			drifts = append(drifts, Drift{version, mm.GetMigrationName(version), DriftEdited})
+		}
+	}
+	for version, rec := range recorded {
+		if !slices.Contains(sequence, version) {
+			drifts = append(drifts, Drift{version, rec.name, DriftMissing})
+		}
+	}
+	slices.SortFunc(drifts, func(a, b Drift) int { return cmp.Compare(a.Version, b.Version) })
+	return drifts, nil
+}
+
+// PrintVerify mostra o resultado do Verify e falha se houver divergência.
+func (mm *MigrationManager) PrintVerify() error {
+	drifts, err := mm.Verify()
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		fmt.Println("✅ Migrações aplicadas conferem com os arquivos")
+		return nil
+	}
+
+	labels := map[string]string{
+		DriftEdited:  "arquivo alterado depois de aplicado",
+		DriftMissing: "aplicada, arquivo não encontrado",
+		DriftUnknown: "mais antiga que a última aplicada, pendente fora de ordem",
+	}
+	fmt.Printf("⚠️ %d migrações divergem do que foi aplicado:\n", len(drifts))
+	for _, drift := range drifts {
+		fmt.Printf("   v%d %-30s %s\n", drift.Version, drift.Name, labels[drift.Kind])
+	}
+	return fmt.Errorf("drift detectado em %d migrações", len(drifts))
+}
+
+// SetAllowDrift faz Up aplicar as pendentes mesmo com divergências no Verify.
+func (mm *MigrationManager) SetAllowDrift(allow bool) {
+	mm.allowDrift = allow
+}
+
+// checkDrift recusa aplicar migrações enquanto o Verify acusar arquivos
+// alterados ou removidos, a menos que SetAllowDrift tenha sido chamado. As
+// pendentes fora de ordem (DriftUnknown) ficam com checkPending.
+func (mm *MigrationManager) checkDrift() error {
+	drifts, err := mm.Verify()
+	if err != nil {
+		return err
+	}
+	var versions []string
+	for _, drift := range drifts {
+		if drift.Kind != DriftUnknown {
+			versions = append(versions, fmt.Sprintf("v%d (%s)", drift.Version, drift.Kind))
+		}
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	if mm.allowDrift {
+		fmt.Printf("⚠️ Ignorando drift em %s\n", strings.Join(versions, ", "))
+		return nil
+	}
+	return fmt.Errorf("drift detectado em %s: rode migrate verify ou use --allow-drift", strings.Join(versions, ", "))
+}
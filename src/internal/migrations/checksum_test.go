@@ -0,0 +1,184 @@
+package migrations
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrationManager_Verify(t *testing.T) {
+	source := maps.Clone(testSource)
+	mm, _ := setupSQLite(t, source)
+	if err := mm.UpN(2); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+	if drifts, err := mm.Verify(); err != nil || len(drifts) != 0 {
+		t.Fatalf("Esperava nenhum drift, obteve %v (%v)", drifts, err)
+	}
+
+	// Só a quebra de linha muda: não é edição
+	if checksum([]byte("CREATE TABLE a (id INTEGER);\r\n")) != checksum([]byte("CREATE TABLE a (id INTEGER);\n")) {
+		t.Error("\\r\\n e \\n deveriam ter o mesmo checksum")
+	}
+
+	// v1 editada e v2 removida do disco
+	source["1_a.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE a (id INTEGER, nome TEXT);`)}
+	delete(source, "2_b.up.sql")
+	delete(source, "2_b.down.sql")
+
+	drifts, err := mm.Verify()
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	want := []Drift{{1, "a", DriftEdited}, {2, "b", DriftMissing}}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Fatalf("Esperava %v, obteve %v", want, drifts)
+	}
+
+	if err := mm.Up(); err == nil {
+		t.Fatal("Up deveria recusar com drift")
+	}
+	mm.SetAllowDrift(true)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up com --allow-drift: %v", err)
+	}
+}
+
+func TestMigrationManager_VerifyBackfillAndUnknown(t *testing.T) {
+	source := maps.Clone(testSource)
+	delete(source, "2_b.up.sql")
+	mm, db := setupSQLite(t, source)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// Banco migrado antes do controle de checksum
+	if _, err := db.Exec(`DROP TABLE applied_migrations`); err != nil {
+		t.Fatal(err)
+	}
+	if drifts, err := mm.Verify(); err != nil || len(drifts) != 0 {
+		t.Fatalf("Sem applied_migrations não há drift, obteve %v (%v)", drifts, err)
+	}
+	if err := mm.Redo(); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+	var backfilled int
+	db.QueryRow(`SELECT COUNT(*) FROM applied_migrations WHERE backfilled`).Scan(&backfilled)
+	if backfilled != 1 {
+		t.Errorf("Esperava a v1 registrada pelo backfill, obteve %d", backfilled)
+	}
+
+	// Uma migração mais antiga que a atual aparece depois (merge de branch)
+	source["2_b.up.sql"] = testSource["2_b.up.sql"]
+	drifts, err := mm.Verify()
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	want := []Drift{{2, "b", DriftUnknown}}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Fatalf("Esperava %v, obteve %v", want, drifts)
+	}
+}
+
+func TestMigrationManager_LedgerFromLogs(t *testing.T) {
+	source := maps.Clone(testSource)
+	delete(source, "2_b.up.sql")
+	mm, db := setupSQLite(t, source)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := mm.Redo(); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+
+	// Banco de antes do applied_migrations: logs sem a coluna version
+	for _, stmt := range []string{
+		`DROP TABLE applied_migrations`,
+		`ALTER TABLE migration_logs DROP COLUMN version`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	// A v2, de uma branch antiga, nunca rodou embora seja menor que a atual
+	source["2_b.up.sql"] = testSource["2_b.up.sql"]
+	applied, err := mm.GetAppliedMigrations()
+	if err != nil || !reflect.DeepEqual(applied, []uint{1, 3}) {
+		t.Fatalf("Esperava aplicadas [1 3] pelos logs, obteve %v (%v)", applied, err)
+	}
+	drifts, err := mm.Verify()
+	if want := []Drift{{2, "b", DriftUnknown}}; err != nil || !reflect.DeepEqual(drifts, want) {
+		t.Fatalf("Esperava %v, obteve %v (%v)", want, drifts, err)
+	}
+	if err := mm.Up(); err == nil {
+		t.Fatal("Up deveria recusar a v2 fora de ordem")
+	}
+
+	mm.SetAllowOutOfOrder(true)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up com --allow-out-of-order: %v", err)
+	}
+	assertApplied(t, mm, db, 1, 2, 3)
+	var backfilled int
+	db.QueryRow(`SELECT COUNT(*) FROM applied_migrations WHERE backfilled`).Scan(&backfilled)
+	if backfilled != 2 {
+		t.Errorf("Esperava v1 e v3 vindas dos logs, obteve %d", backfilled)
+	}
+}
+
+func TestMigrationManager_LedgerFromBaselineLogs(t *testing.T) {
+	mm, db := setupSQLite(t, testSource)
+	if err := mm.UpN(2); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+
+	// migration_logs como o gerenciador antigo a criava e preenchia: sem
+	// direction nem version, só ups, from_version com a migração e
+	// to_version com a versão anterior ao lote
+	for _, stmt := range []string{
+		`DROP TABLE applied_migrations`,
+		`DROP TABLE migration_logs`,
+		`CREATE TABLE migration_logs (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                from_version BIGINT,
+                to_version BIGINT,
+                migration_name VARCHAR(255) NOT NULL,
+                applied_by VARCHAR(100) NOT NULL,
+                started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                completed_at TIMESTAMP,
+                execution_time INTERVAL,
+                success BOOLEAN NOT NULL DEFAULT false,
+                error_message TEXT,
+                environment VARCHAR(50) DEFAULT 'development'
+            )`,
+		`INSERT INTO migration_logs (from_version, to_version, migration_name, applied_by, success)
+            VALUES (1, 0, 'a', 'dev', true), (2, 0, 'b', 'dev', true)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	applied, err := mm.GetAppliedMigrations()
+	if err != nil || !reflect.DeepEqual(applied, []uint{1, 2}) {
+		t.Fatalf("Esperava aplicadas [1 2] pelos logs antigos, obteve %v (%v)", applied, err)
+	}
+	pending, err := mm.GetPendingMigrations()
+	if err != nil || !reflect.DeepEqual(pending, []uint{3}) {
+		t.Fatalf("Esperava só a v3 pendente, obteve %v (%v)", pending, err)
+	}
+
+	// Up acrescenta as colunas novas sem reler as linhas antigas como downs
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	assertApplied(t, mm, db, 1, 2, 3)
+	if _, err := db.Exec(`DROP TABLE applied_migrations`); err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := mm.GetAppliedMigrations(); err != nil || !reflect.DeepEqual(applied, []uint{1, 2, 3}) {
+		t.Fatalf("Esperava aplicadas [1 2 3] pelos logs, obteve %v (%v)", applied, err)
+	}
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+SetDryRun liga o modo dry-run: Up, UpN, DownN, DownAll, Goto e Redo só
+mostram, em ordem, os arquivos e o SQL que executariam. Com validate o
+plano também roda numa transação que é sempre desfeita, o que valida a
+sintaxe e a dependência entre as migrações sem alterar o banco:
+
+	mm.SetDryRun(true, true)
+	mm.Up()
+*/
+func (mm *MigrationManager) SetDryRun(enabled, validate bool) {
+	mm.dryRun = enabled
+	mm.validate = enabled && validate
+}
+
+// apply executa os passos ou, no dry-run, mostra o plano.
+func (mm *MigrationManager) apply(steps []migrationStep) error {
+	if !mm.dryRun {
+		return mm.executeMigrationsInTransaction(steps)
+	}
+	if err := mm.printPlan(steps); err != nil {
+		return err
+	}
+	if mm.validate {
+		return mm.inTransaction(steps, false)
+	}
+	return nil
+}
+
+// printPlan mostra cada arquivo do plano seguido do seu SQL.
+func (mm *MigrationManager) printPlan(steps []migrationStep) error {
+	fmt.Printf("🧪 Dry-run: %d migrações seriam executadas\n", len(steps))
+	for i, step := range steps {
+		file, content, err := mm.readMigrationFile(step.version, step.direction)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n── %d/%d %s (%s: versão %d → %d)\n", i+1, len(steps), file, step.direction, step.from, step.to)
+		fmt.Println(strings.TrimSpace(string(content)))
+	}
+	fmt.Println()
+	return nil
+}
+
+// finish mostra a mensagem de sucesso do comando; no dry-run, avisa que
+// nada foi gravado.
+func (mm *MigrationManager) finish(msg string) {
+	if mm.dryRun {
+		fmt.Println("🧪 Dry-run: nenhuma alteração foi gravada")
+		return
+	}
+	fmt.Println(msg)
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"maps"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDryRun_DoesNotApply(t *testing.T) {
+	mm, db := setupSQLite(t, testSource)
+
+	mm.SetDryRun(true, false)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up --dry-run: %v", err)
+	}
+	assertApplied(t, mm, db)
+
+	mm.SetDryRun(true, true)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up --validate: %v", err)
+	}
+	assertApplied(t, mm, db)
+
+	mm.SetDryRun(false, false)
+	if err := mm.UpN(1); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+	assertApplied(t, mm, db, 1)
+}
+
+func TestDryRun_ValidateReportsErrors(t *testing.T) {
+	source := maps.Clone(testSource)
+	source["4_quebrada.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE d (id INTEGER;`)}
+	mm, db := setupSQLite(t, source)
+
+	// Sem validar, o plano é só mostrado
+	mm.SetDryRun(true, false)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up --dry-run: %v", err)
+	}
+
+	mm.SetDryRun(true, true)
+	if err := mm.Up(); err == nil {
+		t.Fatal("Up --validate deveria acusar o SQL inválido")
+	}
+	assertApplied(t, mm, db)
+}
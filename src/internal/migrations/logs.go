@@ -0,0 +1,114 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// logMigrationStart registra o início de um passo e devolve o id do log
+func (mm *MigrationManager) logMigrationStart(tx *sql.Tx, step migrationStep, name, appliedBy string) (int64, error) {
+	if err := mm.ensureMigrationLogsTable(tx); err != nil {
+		return 0, err
+	}
+	query := `
+        INSERT INTO migration_logs
+        (from_version, to_version, migration_name, applied_by, started_at, success, direction, version)
+        VALUES ($1, $2, $3, $4, $5, false, $6, $7)
+        RETURNING id
+    `
+	var logID int64
+	err := tx.QueryRow(query, step.from, step.to, name, appliedBy, time.Now(), step.direction, step.version).Scan(&logID)
+	return logID, err
+}
+
+// logQuerier é o que appliedFromLogs precisa de *sql.Tx e de querier.
+type logQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+/*
+appliedFromLogs reconstrói as migrações aplicadas a partir dos passos bem
+sucedidos de migration_logs: vale o último passo de cada versão. Os logs
+do gerenciador antigo não têm direction (coluna ausente ou NULL) e só
+registravam ups, com a migração em from_version e a versão anterior em
+to_version. Os gravados antes da coluna version identificam a migração
+por to_version (up) ou from_version (down). Uma versão sem nenhum log não
+entra, mesmo que seja menor que schema_migrations.
+*/
+func (mm *MigrationManager) appliedFromLogs(q logQuerier) (map[uint]appliedRecord, error) {
+	exists, err := tableExists(q, mm.sqlite, "migration_logs")
+	if err != nil || !exists {
+		return map[uint]appliedRecord{}, err
+	}
+	cols := map[string]string{"version": "NULL", "direction": "NULL"}
+	for name := range cols {
+		if ok, err := mm.logColumnExists(q, name); err != nil {
+			return nil, err
+		} else if ok {
+			cols[name] = name
+		}
+	}
+
+	rows, err := q.QueryContext(context.Background(), `
+        SELECT `+cols["version"]+`, from_version, to_version, `+cols["direction"]+`, migration_name
+        FROM migration_logs WHERE success ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration_logs: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint]appliedRecord)
+	for rows.Next() {
+		var version, from, to sql.NullInt64
+		var direction sql.NullString
+		var name string
+		if err := rows.Scan(&version, &from, &to, &direction, &name); err != nil {
+			return nil, err
+		}
+		up := direction.String != "down"
+		switch {
+		case version.Valid:
+		case !direction.Valid, !up:
+			version = from
+		default:
+			version = to
+		}
+		if up {
+			applied[uint(version.Int64)] = appliedRecord{name: name}
+		} else {
+			delete(applied, uint(version.Int64))
+		}
+	}
+	return applied, rows.Err()
+}
+
+// logMigrationResult atualiza o log com o resultado
+func (mm *MigrationManager) logMigrationResult(tx *sql.Tx, logID int64, executionTime time.Duration, migrationErr error) error {
+	success := migrationErr == nil || migrationErr == migrate.ErrNoChange
+	errorMsg := ""
+	if migrationErr != nil && migrationErr != migrate.ErrNoChange {
+		errorMsg = migrationErr.Error()
+	}
+
+	query := `
+        UPDATE migration_logs
+        SET completed_at = $1, execution_time = $2, success = $3, error_message = $4
+        WHERE id = $5
+    `
+	_, err := tx.Exec(query, time.Now(), mm.executionTime(executionTime), success, errorMsg, logID)
+	return err
+}
+
+// executionTime converte a duração para a coluna execution_time: INTERVAL
+// no Postgres, nanossegundos no SQLite.
+func (mm *MigrationManager) executionTime(d time.Duration) any {
+	if mm.sqlite {
+		return int64(d)
+	}
+	return fmt.Sprintf("%d microseconds", d.Microseconds())
+}
@@ -0,0 +1,249 @@
+package migrations
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// migrationStep é um arquivo de migração a executar e as versões do banco
+// antes e depois dele (0 = nenhuma migração aplicada).
+type migrationStep struct {
+	version   uint
+	direction string // "up" ou "down"
+	from, to  uint
+}
+
+// upSteps aplica versions, em ordem, a partir da versão current. Uma
+// versão fora de ordem (menor que current) não rebaixa a versão do banco.
+func upSteps(versions []uint, current uint) []migrationStep {
+	steps := make([]migrationStep, len(versions))
+	for i, version := range versions {
+		to := max(current, version)
+		steps[i] = migrationStep{version: version, direction: "up", from: current, to: to}
+		current = to
+	}
+	return steps
+}
+
+// outOfOrder devolve as pendentes mais antigas que a versão current.
+func outOfOrder(pending []uint, current uint) []uint {
+	var older []uint
+	for _, version := range pending {
+		if version < current {
+			older = append(older, version)
+		}
+	}
+	return older
+}
+
+// SetAllowOutOfOrder faz Up e Goto aplicarem pendentes mais antigas que a
+// versão atual, como as de uma branch criada antes da última migração.
+func (mm *MigrationManager) SetAllowOutOfOrder(allow bool) {
+	mm.allowOutOfOrder = allow
+}
+
+// checkPending recusa aplicar pending com drift (ver checkDrift) ou com
+// migrações fora de ordem, a menos que SetAllowOutOfOrder tenha sido chamado.
+func (mm *MigrationManager) checkPending(pending []uint, current uint) error {
+	if err := mm.checkDrift(); err != nil {
+		return err
+	}
+	older := outOfOrder(pending, current)
+	if len(older) == 0 {
+		return nil
+	}
+	if !mm.allowOutOfOrder {
+		return fmt.Errorf("migrações %v são mais antigas que a versão atual %d: use --allow-out-of-order para aplicá-las", older, current)
+	}
+	fmt.Printf("⚠️ Aplicando fora de ordem: %v (versão atual %d)\n", older, current)
+	return nil
+}
+
+// downSteps reverte as últimas n migrações de applied (em ordem crescente),
+// da mais nova para a mais antiga.
+func downSteps(applied []uint, n int) []migrationStep {
+	n = min(n, len(applied))
+	steps := make([]migrationStep, 0, n)
+	for i := len(applied) - 1; i >= len(applied)-n; i-- {
+		var previous uint
+		if i > 0 {
+			previous = applied[i-1]
+		}
+		steps = append(steps, migrationStep{version: applied[i], direction: "down", from: applied[i], to: previous})
+	}
+	return steps
+}
+
+// lastVersion devolve a última versão de versions, ou 0 se vazia.
+func lastVersion(versions []uint) uint {
+	if len(versions) == 0 {
+		return 0
+	}
+	return versions[len(versions)-1]
+}
+
+// currentVersion é a maior versão entre applied e schema_migrations, que
+// num banco antigo pode estar à frente do que migration_logs comprova.
+func (mm *MigrationManager) currentVersion(applied []uint) uint {
+	version, _, err := mm.m.Version()
+	if err != nil {
+		version = 0
+	}
+	return max(version, lastVersion(applied))
+}
+
+// cleanState devolve as migrações aplicadas, recusando um banco dirty.
+func (mm *MigrationManager) cleanState() ([]uint, error) {
+	currentVersion, dirty, err := mm.m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return nil, fmt.Errorf("failed to get current version: %v", err)
+	}
+	if dirty {
+		return nil, fmt.Errorf("database is in dirty state (version %d). Please clean it first", currentVersion)
+	}
+	return mm.GetAppliedMigrations()
+}
+
+// UpN aplica as próximas n migrações pendentes; n = 0 aplica todas.
+func (mm *MigrationManager) UpN(n int) error {
+	if n < 0 {
+		return fmt.Errorf("número de migrações inválido: %d", n)
+	}
+	applied, err := mm.cleanState()
+	if err != nil {
+		return err
+	}
+	pending, err := mm.GetPendingMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to get pending migrations: %v", err)
+	}
+	if len(pending) == 0 {
+		fmt.Println("✅ Nenhuma migração pendente")
+		return nil
+	}
+
+	fmt.Printf("📋 Migrações pendentes encontradas: %d\n", len(pending))
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+		fmt.Printf("📋 Aplicando apenas as próximas %d\n", n)
+	}
+	current := mm.currentVersion(applied)
+	if err := mm.checkPending(pending, current); err != nil {
+		return err
+	}
+
+	if err := mm.apply(upSteps(pending, current)); err != nil {
+		return fmt.Errorf("❌ Migration failed: %v", err)
+	}
+	mm.finish("✅ Todas as migrações foram aplicadas com sucesso")
+	return nil
+}
+
+// DownN reverte as últimas n migrações aplicadas.
+func (mm *MigrationManager) DownN(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("número de migrações inválido: %d", n)
+	}
+	applied, err := mm.cleanState()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("✅ Nenhuma migração para reverter")
+		return nil
+	}
+
+	steps := downSteps(applied, n)
+	fmt.Printf("📋 Revertendo %d migração(ões), até a versão %d\n", len(steps), steps[len(steps)-1].to)
+
+	if err := mm.apply(steps); err != nil {
+		return fmt.Errorf("❌ Migration down failed: %v", err)
+	}
+	mm.finish("✅ Migrações revertidas com sucesso")
+	return nil
+}
+
+// DownAll reverte todas as migrações aplicadas.
+func (mm *MigrationManager) DownAll() error {
+	return mm.DownN(math.MaxInt)
+}
+
+// Goto aplica ou reverte migrações até o banco ficar em version.
+// Goto(0) reverte todas.
+func (mm *MigrationManager) Goto(version uint) error {
+	applied, err := mm.cleanState()
+	if err != nil {
+		return err
+	}
+	sequence, err := mm.GetMigrationSequence()
+	if err != nil {
+		return err
+	}
+	if version != 0 && !slices.Contains(sequence, version) {
+		return fmt.Errorf("versão %d não encontrada nas migrações", version)
+	}
+
+	current := mm.currentVersion(applied)
+	var steps []migrationStep
+	switch {
+	case version > current:
+		pending, err := mm.GetPendingMigrations()
+		if err != nil {
+			return fmt.Errorf("failed to get pending migrations: %v", err)
+		}
+		var target []uint
+		for _, v := range pending {
+			if v <= version {
+				target = append(target, v)
+			}
+		}
+		if err := mm.checkPending(target, current); err != nil {
+			return err
+		}
+		steps = upSteps(target, current)
+	case version < current:
+		n := 0
+		for _, v := range applied {
+			if v > version {
+				n++
+			}
+		}
+		steps = downSteps(applied, n)
+	default:
+		fmt.Printf("✅ Banco já está na versão %d\n", version)
+		return nil
+	}
+
+	fmt.Printf("📋 Indo da versão %d para a versão %d (%d migrações)\n", current, version, len(steps))
+	if err := mm.apply(steps); err != nil {
+		return fmt.Errorf("❌ Migration failed: %v", err)
+	}
+	mm.finish(fmt.Sprintf("✅ Banco na versão %d", version))
+	return nil
+}
+
+// Redo reverte e reaplica a última migração na mesma transação, útil
+// enquanto a migração ainda está sendo escrita.
+func (mm *MigrationManager) Redo() error {
+	applied, err := mm.cleanState()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("✅ Nenhuma migração para refazer")
+		return nil
+	}
+
+	steps := downSteps(applied, 1)
+	steps = append(steps, upSteps([]uint{steps[0].version}, steps[0].to)...)
+	fmt.Printf("📋 Refazendo migração: v%d (%s)\n", steps[0].version, mm.GetMigrationName(steps[0].version))
+
+	if err := mm.apply(steps); err != nil {
+		return fmt.Errorf("❌ Migration redo failed: %v", err)
+	}
+	mm.finish("✅ Migração refeita com sucesso")
+	return nil
+}
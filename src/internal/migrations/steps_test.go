@@ -0,0 +1,206 @@
+package migrations
+
+import (
+	"database/sql"
+	"io/fs"
+	"maps"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+)
+
+// testSource tem três migrações, cada uma criando uma tabela.
+var testSource = fstest.MapFS{
+	"1_a.up.sql":   {Data: []byte(`CREATE TABLE a (id INTEGER);`)},
+	"1_a.down.sql": {Data: []byte(`DROP TABLE a;`)},
+	"2_b.up.sql":   {Data: []byte(`CREATE TABLE b (id INTEGER);`)},
+	"2_b.down.sql": {Data: []byte(`DROP TABLE b;`)},
+	"3_c.up.sql":   {Data: []byte(`CREATE TABLE c (id INTEGER);`)},
+	"3_c.down.sql": {Data: []byte(`DROP TABLE c;`)},
+}
+
+// setupSQLite cria um gerenciador das migrações de source sobre um SQLite em memória.
+func setupSQLite(t *testing.T, source fs.FS) (*MigrationManager, *sql.DB) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("falha ao abrir sqlite: %s", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	mm, err := NewMigrationManager(db, source, "sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("NewMigrationManager: %v", err)
+	}
+	t.Cleanup(func() { mm.Close() })
+	return mm, db
+}
+
+// assertApplied confere as migrações aplicadas e as tabelas existentes.
+func assertApplied(t *testing.T, mm *MigrationManager, db *sql.DB, want ...uint) {
+	t.Helper()
+	applied, err := mm.GetAppliedMigrations()
+	if err != nil {
+		t.Fatalf("GetAppliedMigrations: %v", err)
+	}
+	if len(applied) != len(want) || (len(want) > 0 && !reflect.DeepEqual(applied, want)) {
+		t.Fatalf("Esperava aplicadas %v, obteve %v", want, applied)
+	}
+	var tables int
+	db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name IN ('a', 'b', 'c')`).Scan(&tables)
+	if tables != len(want) {
+		t.Fatalf("Esperava %d tabelas criadas, obteve %d", len(want), tables)
+	}
+}
+
+func TestSteps(t *testing.T) {
+	up := upSteps([]uint{2, 3}, 1)
+	wantUp := []migrationStep{{2, "up", 1, 2}, {3, "up", 2, 3}}
+	if !reflect.DeepEqual(up, wantUp) {
+		t.Errorf("upSteps: esperava %v, obteve %v", wantUp, up)
+	}
+
+	// Fora de ordem: a versão do banco continua em 5
+	late := upSteps([]uint{2, 7}, 5)
+	wantLate := []migrationStep{{2, "up", 5, 5}, {7, "up", 5, 7}}
+	if !reflect.DeepEqual(late, wantLate) {
+		t.Errorf("upSteps fora de ordem: esperava %v, obteve %v", wantLate, late)
+	}
+
+	down := downSteps([]uint{1, 2, 3}, 5)
+	wantDown := []migrationStep{{3, "down", 3, 2}, {2, "down", 2, 1}, {1, "down", 1, 0}}
+	if !reflect.DeepEqual(down, wantDown) {
+		t.Errorf("downSteps: esperava %v, obteve %v", wantDown, down)
+	}
+}
+
+func TestMigrationManager_StepsAndGoto(t *testing.T) {
+	mm, db := setupSQLite(t, testSource)
+
+	if err := mm.UpN(2); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+	assertApplied(t, mm, db, 1, 2)
+
+	if err := mm.Redo(); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+	assertApplied(t, mm, db, 1, 2)
+
+	if err := mm.Goto(3); err != nil {
+		t.Fatalf("Goto(3): %v", err)
+	}
+	assertApplied(t, mm, db, 1, 2, 3)
+
+	if err := mm.DownN(2); err != nil {
+		t.Fatalf("DownN: %v", err)
+	}
+	assertApplied(t, mm, db, 1)
+
+	if err := mm.Goto(9); err == nil {
+		t.Error("Goto para versão inexistente deveria falhar")
+	}
+
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := mm.DownAll(); err != nil {
+		t.Fatalf("DownAll: %v", err)
+	}
+	assertApplied(t, mm, db)
+
+	// Cada passo fica em migration_logs com a direção
+	var ups, downs int
+	db.QueryRow(`SELECT COUNT(*) FROM migration_logs WHERE direction = 'up' AND success`).Scan(&ups)
+	db.QueryRow(`SELECT COUNT(*) FROM migration_logs WHERE direction = 'down' AND success`).Scan(&downs)
+	if ups != 6 || downs != 6 {
+		t.Errorf("Esperava 6 ups e 6 downs em migration_logs, obteve %d e %d", ups, downs)
+	}
+}
+
+func TestMigrationManager_FailureRollsBack(t *testing.T) {
+	mm, db := setupSQLite(t, testSource)
+	if err := mm.UpN(1); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+
+	// A tabela b já existe: a migração 2 falha e nada da transação fica
+	if _, err := db.Exec(`CREATE TABLE b (id INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := mm.Up(); err == nil {
+		t.Fatal("Up deveria falhar")
+	}
+	applied, _ := mm.GetAppliedMigrations()
+	if !reflect.DeepEqual(applied, []uint{1}) {
+		t.Errorf("Esperava continuar na versão 1, obteve %v", applied)
+	}
+}
+
+func TestMigrationManager_OutOfOrder(t *testing.T) {
+	source := maps.Clone(testSource)
+	delete(source, "2_b.up.sql")
+	mm, db := setupSQLite(t, source)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// A migração 2 chega depois da 3 (branch mergeada)
+	source["2_b.up.sql"] = testSource["2_b.up.sql"]
+	pending, err := mm.GetPendingMigrations()
+	if err != nil || !reflect.DeepEqual(pending, []uint{2}) {
+		t.Fatalf("Esperava a v2 pendente, obteve %v (%v)", pending, err)
+	}
+	if err := mm.Up(); err == nil {
+		t.Fatal("Up deveria recusar migração fora de ordem")
+	}
+
+	mm.SetAllowOutOfOrder(true)
+	if err := mm.Up(); err != nil {
+		t.Fatalf("Up com --allow-out-of-order: %v", err)
+	}
+	assertApplied(t, mm, db, 1, 2, 3)
+	if version, _, _ := mm.m.Version(); version != 3 {
+		t.Errorf("Esperava schema_migrations na versão 3, obteve %d", version)
+	}
+
+	// Reverter desfaz pela ordem das versões
+	if err := mm.DownN(2); err != nil {
+		t.Fatalf("DownN: %v", err)
+	}
+	assertApplied(t, mm, db, 1)
+}
+
+func TestMigrationManager_DownDropsLogTable(t *testing.T) {
+	// Como o init do repositório, o down da v1 apaga migration_logs
+	source := maps.Clone(testSource)
+	source["1_a.down.sql"] = &fstest.MapFile{Data: []byte(`DROP TABLE a; DROP TABLE IF EXISTS migration_logs;`)}
+	mm, db := setupSQLite(t, source)
+	if err := mm.UpN(2); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+
+	if err := mm.DownAll(); err != nil {
+		t.Fatalf("DownAll: %v", err)
+	}
+	assertApplied(t, mm, db)
+	var logs int
+	db.QueryRow(`SELECT COUNT(*) FROM migration_logs WHERE direction = 'down' AND version = 1 AND success`).Scan(&logs)
+	if logs != 1 {
+		t.Errorf("Esperava o down da v1 registrado na migration_logs recriada, obteve %d", logs)
+	}
+
+	if err := mm.UpN(1); err != nil {
+		t.Fatalf("UpN: %v", err)
+	}
+	if err := mm.Redo(); err != nil {
+		t.Fatalf("Redo: %v", err)
+	}
+	assertApplied(t, mm, db, 1)
+	if err := mm.Goto(0); err != nil {
+		t.Fatalf("Goto(0): %v", err)
+	}
+	assertApplied(t, mm, db)
+}
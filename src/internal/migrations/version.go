@@ -1,29 +1,23 @@
-package main
+package migrations
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
-	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/golang-migrate/migrate/v4"
 )
 
-func (mm *MigrationManager) ensureMigrationLogsTable() error {
-	checkQuery := `SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = 'migration_logs')`
-	var exists bool
-	if err := mm.db.QueryRow(checkQuery).Scan(&exists); err != nil {
-		return err
-	}
-
-	if !exists {
-		fmt.Println("📋 Criando tabela migration_logs...")
-		createQuery := `
+// DDL da tabela migration_logs em cada banco. No SQLite execution_time
+// guarda a duração em nanossegundos (não há INTERVAL).
+const (
+	migrationLogsPostgres = `
             CREATE TABLE migration_logs (
                 id SERIAL PRIMARY KEY,
                 from_version BIGINT,
@@ -35,20 +29,96 @@ func (mm *MigrationManager) ensureMigrationLogsTable() error {
                 execution_time INTERVAL,
                 success BOOLEAN NOT NULL DEFAULT false,
                 error_message TEXT,
-                environment VARCHAR(50) DEFAULT 'development'
+                environment VARCHAR(50) DEFAULT 'development',
+                direction VARCHAR(10) NOT NULL DEFAULT 'up',
+                version BIGINT
             )
         `
-		if _, err := mm.db.Exec(createQuery); err != nil {
+	migrationLogsSQLite = `
+            CREATE TABLE migration_logs (
+                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                from_version BIGINT,
+                to_version BIGINT,
+                migration_name VARCHAR(255) NOT NULL,
+                applied_by VARCHAR(100) NOT NULL,
+                started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                completed_at DATETIME,
+                execution_time BIGINT,
+                success BOOLEAN NOT NULL DEFAULT false,
+                error_message TEXT,
+                environment VARCHAR(50) DEFAULT 'development',
+                direction VARCHAR(10) NOT NULL DEFAULT 'up',
+                version BIGINT
+            )
+        `
+)
+
+// ensureMigrationLogsTable cria migration_logs dentro da transação da
+// migração (num pool de uma conexão, como o SQLite em memória, não há
+// outra conexão livre).
+func (mm *MigrationManager) ensureMigrationLogsTable(tx *sql.Tx) error {
+	checkQuery := `SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = 'migration_logs')`
+	createQuery := migrationLogsPostgres
+	if mm.sqlite {
+		checkQuery = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'migration_logs')`
+		createQuery = migrationLogsSQLite
+	}
+
+	var exists bool
+	if err := tx.QueryRow(checkQuery).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		fmt.Println("📋 Criando tabela migration_logs...")
+		if _, err := tx.Exec(createQuery); err != nil {
 			return fmt.Errorf("failed to create migration_logs table: %v", err)
 		}
 		fmt.Println("✅ Tabela migration_logs criada com sucesso")
+		return nil
+	}
+	return mm.addMissingLogColumns(tx)
+}
+
+// migrationLogsColumns são as colunas acrescentadas a migration_logs depois
+// da primeira versão, criadas em tabelas antigas por addMissingLogColumns.
+var migrationLogsColumns = []struct{ name, ddl string }{
+	{"direction", "direction VARCHAR(10)"}, // NULL nas linhas antigas, ver appliedFromLogs
+	{"version", "version BIGINT"},          // migração do passo; to_version não basta fora de ordem
+}
+
+// addMissingLogColumns atualiza uma migration_logs criada por uma versão
+// anterior do gerenciador.
+func (mm *MigrationManager) addMissingLogColumns(tx *sql.Tx) error {
+	for _, col := range migrationLogsColumns {
+		exists, err := mm.logColumnExists(tx, col.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := tx.Exec("ALTER TABLE migration_logs ADD COLUMN " + col.ddl); err != nil {
+			return fmt.Errorf("failed to add column %s to migration_logs: %v", col.name, err)
+		}
 	}
 	return nil
 }
 
+// logColumnExists informa se migration_logs já tem a coluna name.
+func (mm *MigrationManager) logColumnExists(q logQuerier, name string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'migration_logs' AND column_name = $1)`
+	if mm.sqlite {
+		query = `SELECT EXISTS (SELECT 1 FROM pragma_table_info('migration_logs') WHERE name = $1)`
+	}
+	var exists bool
+	err := q.QueryRowContext(context.Background(), query, name).Scan(&exists)
+	return exists, err
+}
+
 // GetMigrationSequence retorna a sequência ordenada de migrações disponíveis
 func (mm *MigrationManager) GetMigrationSequence() ([]uint, error) {
-	files, err := os.ReadDir(mm.migrationPath)
+	files, err := fs.ReadDir(mm.source, ".")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read migrations directory: %v", err)
 	}
@@ -119,10 +189,18 @@ func (mm *MigrationManager) GetPreviousVersion(currentVersion uint) (uint, error
 	return versions[currentIndex-1], nil
 }
 
-// executeMigrationsInTransaction executa múltiplas migrações em uma transação
-func (mm *MigrationManager) executeMigrationsInTransaction(versions []uint, olderVersion uint, direction string) error {
+// executeMigrationsInTransaction executa os passos em uma única transação,
+// registrando cada um em migration_logs. Qualquer falha desfaz todos.
+func (mm *MigrationManager) executeMigrationsInTransaction(steps []migrationStep) error {
+	return mm.inTransaction(steps, true)
+}
+
+// inTransaction executa os passos numa transação que só é commitada se
+// commit for verdadeiro; caso contrário é sempre desfeita (dry-run).
+func (mm *MigrationManager) inTransaction(steps []migrationStep, commit bool) error {
 	// Iniciar transação
-	tx, err := mm.db.Begin()
+	// Na conexão do golang-migrate: outra conexão do pool pode não existir
+	tx, err := mm.conn.BeginTx(context.Background(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
@@ -138,33 +216,70 @@ func (mm *MigrationManager) executeMigrationsInTransaction(versions []uint, olde
 		}
 	}()
 
-	fmt.Printf("🚀 Iniciando transação com %d migrações...\n", len(versions))
+	fmt.Printf("🚀 Iniciando transação com %d migrações...\n", len(steps))
+	// migration_logs antes: applied_migrations nasce a partir dela
+	if err := mm.ensureMigrationLogsTable(tx); err != nil {
+		return err
+	}
+	if err := mm.ensureAppliedTable(tx); err != nil {
+		return err
+	}
 
-	for i, version := range versions {
-		migrationName := mm.GetMigrationName(version)
+	for i, step := range steps {
+		migrationName := mm.GetMigrationName(step.version)
 		start := time.Now()
-		if err := mm.logMigrationStart(tx, version, olderVersion, migrationName, os.Getenv("USER")); err != nil {
+		logID, err := mm.logMigrationStart(tx, step, migrationName, os.Getenv("USER"))
+		if err != nil {
 			return err
 		}
 
-		fmt.Printf("📦 Aplicando migração %d/%d: %s (v%d)...\n",
-			i+1, len(versions), migrationName, version)
+		action, done := "Aplicando", "aplicada"
+		if step.direction == "down" {
+			action, done = "Revertendo", "revertida"
+		}
+		fmt.Printf("📦 %s migração %d/%d: %s (v%d)...\n",
+			action, i+1, len(steps), migrationName, step.version)
 
 		// Ler e executar o arquivo SQL manualmente na transação
-		if err := mm.executeMigrationFile(tx, version, direction); err != nil {
-			return fmt.Errorf("failed to execute migration %d (%s): %v",
-				version, migrationName, err)
+		if err := mm.executeMigrationFile(tx, step.version, step.direction); err != nil {
+			return fmt.Errorf("failed to execute migration %d (%s) %s: %v",
+				step.version, migrationName, step.direction, err)
+		}
+
+		// O down do init apaga migration_logs: recria a tabela e registra o
+		// passo de novo
+		if exists, err := tableExists(tx, mm.sqlite, "migration_logs"); err != nil {
+			return err
+		} else if !exists {
+			if logID, err = mm.logMigrationStart(tx, step, migrationName, os.Getenv("USER")); err != nil {
+				return err
+			}
 		}
 
 		// Atualizar schema_migrations dentro da transação
-		if err := mm.updateSchemaVersionInTx(tx, version, false); err != nil {
-			return fmt.Errorf("failed to update schema version for %d: %v", version, err)
+		if err := mm.updateSchemaVersionInTx(tx, step.to, false); err != nil {
+			return fmt.Errorf("failed to update schema version for %d: %v", step.to, err)
+		}
+		// Registrar (ou remover) o checksum da migração
+		if step.direction == "down" {
+			err = mm.forgetApplied(tx, step.version)
+		} else {
+			err = mm.recordApplied(tx, step.version, false)
 		}
-		if err := mm.logMigrationResult(tx, version, olderVersion, migrationName, os.Getenv("USER"), time.Since(start), err); err != nil {
+		if err != nil {
+			return err
+		}
+		if err := mm.logMigrationResult(tx, logID, time.Since(start), nil); err != nil {
 			return err
 		}
 
-		fmt.Printf("✅ Migração %d aplicada com sucesso\n", version)
+		fmt.Printf("✅ Migração %d %s com sucesso\n", step.version, done)
+	}
+
+	if !commit {
+		// O defer faz o rollback
+		fmt.Printf("🧪 Validação concluída - %d migrações executadas sem erro\n", len(steps))
+		return nil
 	}
 
 	// Commit da transação
@@ -173,7 +288,7 @@ func (mm *MigrationManager) executeMigrationsInTransaction(versions []uint, olde
 	}
 
 	success = true
-	fmt.Printf("✅ Transação commitada com sucesso - %d migrações aplicadas\n", len(versions))
+	fmt.Printf("✅ Transação commitada com sucesso - %d migrações executadas\n", len(steps))
 	return nil
 }
 
@@ -215,32 +330,40 @@ func (mm *MigrationManager) GetNextVersion(currentVersion uint) (uint, error) {
 	return versions[currentIndex+1], nil
 }
 
-// executeMigrationFile lê e executa um arquivo de migração na transação
-func (mm *MigrationManager) executeMigrationFile(tx *sql.Tx, version uint, direction string) error {
-	// Encontrar o arquivo de migração
-	filename := fmt.Sprintf("%d_", version)
-	files, err := os.ReadDir(mm.migrationPath)
+// readMigrationFile encontra e lê o arquivo <versão>_<nome>.<direção>.sql
+func (mm *MigrationManager) readMigrationFile(version uint, direction string) (string, []byte, error) {
+	prefix := fmt.Sprintf("%d_", version)
+	suffix := fmt.Sprintf(".%s.sql", direction)
+	files, err := fs.ReadDir(mm.source, ".")
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	var migrationFile string
 	for _, file := range files {
-		if strings.Contains(file.Name(), filename) &&
-			strings.HasSuffix(file.Name(), fmt.Sprintf(".%s.sql", direction)) {
+		if strings.HasPrefix(file.Name(), prefix) && strings.HasSuffix(file.Name(), suffix) {
 			migrationFile = file.Name()
 			break
 		}
 	}
 
 	if migrationFile == "" {
-		return fmt.Errorf("migration file not found for version %d direction %s", version, direction)
+		return "", nil, fmt.Errorf("migration file not found for version %d direction %s", version, direction)
 	}
 
 	// Ler conteúdo do arquivo
-	content, err := os.ReadFile(filepath.Join(mm.migrationPath, migrationFile))
+	content, err := fs.ReadFile(mm.source, migrationFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read migration file %s: %v", migrationFile, err)
+	}
+	return migrationFile, content, nil
+}
+
+// executeMigrationFile lê e executa um arquivo de migração na transação
+func (mm *MigrationManager) executeMigrationFile(tx *sql.Tx, version uint, direction string) error {
+	migrationFile, content, err := mm.readMigrationFile(version, direction)
 	if err != nil {
-		return fmt.Errorf("failed to read migration file %s: %v", migrationFile, err)
+		return err
 	}
 
 	// Executar SQL na transação
@@ -253,6 +376,13 @@ func (mm *MigrationManager) executeMigrationFile(tx *sql.Tx, version uint, direc
 
 // updateSchemaVersionInTx atualiza a tabela schema_migrations na transação
 func (mm *MigrationManager) updateSchemaVersionInTx(tx *sql.Tx, version uint, dirty bool) error {
+	// Versão 0 = nenhuma migração aplicada: o golang-migrate representa
+	// isso com a tabela vazia
+	if version == 0 {
+		_, err := tx.Exec(`DELETE FROM schema_migrations`)
+		return err
+	}
+
 	// A sintaxe correta define explicitamente coluna = valor
 	query := `
         UPDATE schema_migrations 
@@ -286,10 +416,11 @@ func (mm *MigrationManager) updateSchemaVersionInTx(tx *sql.Tx, version uint, di
 	return nil
 }
 
-// GetPendingMigrations retorna migrações pendentes em ordem
+// GetPendingMigrations retorna, em ordem, as migrações do disco que não
+// estão em GetAppliedMigrations, inclusive as mais antigas que a versão atual.
 func (mm *MigrationManager) GetPendingMigrations() ([]uint, error) {
-	currentVersion, _, err := mm.m.Version()
-	if err != nil && err != migrate.ErrNilVersion {
+	applied, err := mm.GetAppliedMigrations()
+	if err != nil {
 		return nil, err
 	}
 
@@ -300,7 +431,7 @@ func (mm *MigrationManager) GetPendingMigrations() ([]uint, error) {
 
 	var pending []uint
 	for _, version := range allVersions {
-		if version > currentVersion {
+		if !slices.Contains(applied, version) {
 			pending = append(pending, version)
 		}
 	}
@@ -308,9 +439,24 @@ func (mm *MigrationManager) GetPendingMigrations() ([]uint, error) {
 	return pending, nil
 }
 
+// migrationStatus descreve version na listagem de status.
+func migrationStatus(version, currentVersion uint, dirty bool, applied []uint) string {
+	switch {
+	case version == currentVersion && dirty:
+		return "Dirty"
+	case version == currentVersion && slices.Contains(applied, version):
+		return "Atual"
+	case slices.Contains(applied, version):
+		return "Aplicada"
+	case version < currentVersion:
+		return "Fora de ordem"
+	}
+	return "Pendente"
+}
+
 // GetMigrationName obtém o nome da migração baseado na versão
 func (mm *MigrationManager) GetMigrationName(version uint) string {
-	files, err := os.ReadDir(mm.migrationPath)
+	files, err := fs.ReadDir(mm.source, ".")
 	if err != nil {
 		return fmt.Sprintf("migration_%d", version)
 	}
@@ -341,6 +487,10 @@ func (mm *MigrationManager) PrintMigrationSequence() error {
 	}
 
 	currentVersion, dirty, _ := mm.m.Version()
+	applied, err := mm.GetAppliedMigrations()
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("\n📋 Sequência de Migrações:")
 	fmt.Println("┌────┬──────────────┬──────────────────────┬──────────┐")
@@ -348,15 +498,12 @@ func (mm *MigrationManager) PrintMigrationSequence() error {
 	fmt.Println("├────┼──────────────┼──────────────────────┼──────────┤")
 
 	for i, version := range versions {
-		status := "Pendente"
-		if version == currentVersion {
-			if dirty {
-				status = "⚠️ Dirty"
-			} else {
-				status = "✅ Atual"
-			}
-		} else if version < currentVersion {
-			status = "✅ Aplicada"
+		status := migrationStatus(version, currentVersion, dirty, applied)
+		switch status {
+		case "Dirty":
+			status = "⚠️ Dirty"
+		case "Atual", "Aplicada":
+			status = "✅ " + status
 		}
 
 		name := mm.GetMigrationName(version)
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+/*
+GenEntityScript gera Columns(), ScanRow() e Values() a partir das tags `db`
+das structs, para que a ordem das colunas nunca fique dessincronizada.
+Feito para ser chamado pelo go generate, no arquivo da entidade:
+
+	//go:generate go run deskapp/src/internal/scripts gen-entity -type Usuario
+
+	type Usuario struct {
+		ID    int64  `db:"id" json:"id"`
+		Nome  string `db:"nome" json:"nome"`
+		Senha string `db:"senha" json:"-"`
+	}
+
+O resultado vai para <arquivo>_gen.go (ou -output). Sem -type, todas as
+structs do arquivo com ao menos uma tag `db` são geradas.
+*/
+type GenEntityScript struct {
+	ScriptBase
+}
+
+func (s *GenEntityScript) Name() string { return "gen-entity" }
+func (s *GenEntityScript) Description() string {
+	return "Gera Columns/ScanRow/Values a partir das tags db (go generate)"
+}
+
+// genEntityType é uma struct encontrada no arquivo com suas colunas
+type genEntityType struct {
+	Name   string
+	Fields []genEntityField
+}
+
+type genEntityField struct {
+	GoName string
+	Column string
+}
+
+func (s *GenEntityScript) Execute(args []string) error {
+	fs := flag.NewFlagSet(s.Name(), flag.ContinueOnError)
+	file := fs.String("file", os.Getenv("GOFILE"), "arquivo com as structs (padrão: $GOFILE do go generate)")
+	types := fs.String("type", "", "structs a gerar, separadas por vírgula (padrão: todas com tag db)")
+	output := fs.String("output", "", "arquivo de saída (padrão: <arquivo>_gen.go)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("informe -file ou execute via go generate")
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(*file, ".go") + "_gen.go"
+	}
+
+	var wanted []string
+	if *types != "" {
+		wanted = strings.Split(*types, ",")
+	}
+
+	pkg, found, err := parseEntityStructs(*file, wanted)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("nenhuma struct com tags db encontrada em %s", *file)
+	}
+
+	importPath, err := packageImportPath(filepath.Dir(*file))
+	if err != nil {
+		return err
+	}
+	src, err := renderEntityCode(pkg, importPath, found)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		return fmt.Errorf("erro ao escrever %s: %v", *output, err)
+	}
+	fmt.Printf("✅ %d entidade(s) gerada(s) em: %s\n", len(found), *output)
+	return nil
+}
+
+// parseEntityStructs lê o arquivo e extrai as structs com suas colunas `db`.
+func parseEntityStructs(file string, wanted []string) (string, []genEntityType, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return "", nil, fmt.Errorf("erro ao ler %s: %v", file, err)
+	}
+
+	var found []genEntityType
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || (wanted != nil && !slices.Contains(wanted, ts.Name.Name)) {
+				continue
+			}
+
+			entity := genEntityType{Name: ts.Name.Name}
+			for _, field := range st.Fields.List {
+				column := dbTagColumn(field)
+				if column == "" {
+					continue
+				}
+				if len(field.Names) == 0 {
+					return "", nil, fmt.Errorf("%s: campo embutido com tag db não é suportado", ts.Name.Name)
+				}
+				if slices.ContainsFunc(entity.Fields, func(f genEntityField) bool { return f.Column == column }) || len(field.Names) > 1 {
+					return "", nil, fmt.Errorf("%s: coluna %q repetida nas tags db", ts.Name.Name, column)
+				}
+				entity.Fields = append(entity.Fields, genEntityField{GoName: field.Names[0].Name, Column: column})
+			}
+
+			if len(entity.Fields) == 0 {
+				if wanted != nil {
+					return "", nil, fmt.Errorf("%s não possui campos com tag db", ts.Name.Name)
+				}
+				continue
+			}
+			found = append(found, entity)
+		}
+	}
+
+	for _, name := range wanted {
+		if !slices.ContainsFunc(found, func(e genEntityType) bool { return e.Name == name }) {
+			return "", nil, fmt.Errorf("struct %s não encontrada em %s", name, file)
+		}
+	}
+	return f.Name.Name, found, nil
+}
+
+// dbTagColumn devolve o nome da coluna da tag `db` ("" se não houver ou for "-").
+func dbTagColumn(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	column, _, _ := strings.Cut(reflect.StructTag(raw).Get("db"), ",")
+	if column == "-" {
+		return ""
+	}
+	return column
+}
+
+// entitiesImportPath é o pacote de DBScanner; as entidades dele mesmo não o importam.
+const entitiesImportPath = "deskapp/src/apps/core/model/entities"
+
+// packageImportPath devolve o import path do diretório dir a partir do go.mod
+// mais próximo ("" se não houver go.mod).
+func packageImportPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := dir; ; root = filepath.Dir(root) {
+		mod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(modulePath(mod), filepath.ToSlash(rel)), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		if filepath.Dir(root) == root {
+			return "", nil
+		}
+	}
+}
+
+// modulePath lê a diretiva module de um go.mod.
+func modulePath(mod []byte) string {
+	for line := range strings.Lines(string(mod)) {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(name), `"`)
+		}
+	}
+	return ""
+}
+
+const genEntityTemplate = `// Code generated by gen-entity; DO NOT EDIT.
+
+package {{.Package}}
+{{if ne .Scanner "DBScanner"}}
+import "` + entitiesImportPath + `"
+{{end}}
+{{- range .Types}}
+// Columns retorna a lista de colunas na ordem exata do ScanRow.
+func (m *{{.Name}}) Columns() []string {
+	return []string{
+{{- range .Fields}}
+		"{{.Column}}",
+{{- end}}
+	}
+}
+
+// ScanRow implementa a lógica de scan para um DBScanner (*sql.Row ou *sql.Rows).
+func (m *{{.Name}}) ScanRow(row {{$.Scanner}}) error {
+	return row.Scan(
+{{- range .Fields}}
+		&m.{{.GoName}},
+{{- end}}
+	)
+}
+
+// Values retorna os valores das colunas na ordem de Columns().
+func (m *{{.Name}}) Values() []any {
+	return []any{
+{{- range .Fields}}
+		m.{{.GoName}},
+{{- end}}
+	}
+}
+{{end}}`
+
+// renderEntityCode executa o template e formata o código gerado para o
+// pacote pkg, de import path importPath.
+func renderEntityCode(pkg, importPath string, types []genEntityType) ([]byte, error) {
+	tmpl := template.Must(template.New("gen-entity").Parse(genEntityTemplate))
+
+	scanner := "entities.DBScanner"
+	if importPath == entitiesImportPath {
+		scanner = "DBScanner"
+	}
+	var buf bytes.Buffer
+	data := struct {
+		Package string
+		Scanner string
+		Types   []genEntityType
+	}{pkg, scanner, types}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("erro ao executar template: %v", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao formatar código gerado: %v", err)
+	}
+	return src, nil
+}
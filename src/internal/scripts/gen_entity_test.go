@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regrava os arquivos .golden de testdata")
+
+// checkGolden compara got com testdata/gen_entity/name (ou o regrava com -update).
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", "gen_entity", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Código gerado difere de %s:\n%s", golden, got)
+	}
+}
+
+func TestGenEntity_Golden(t *testing.T) {
+	pkg, types, err := parseEntityStructs(filepath.Join("testdata", "gen_entity", "usuario.go"), nil)
+	if err != nil {
+		t.Fatalf("parseEntityStructs: %v", err)
+	}
+	if pkg != "cadastro" || len(types) != 2 {
+		t.Fatalf("Esperava Usuario e Grupo em cadastro, obteve %s %+v", pkg, types)
+	}
+
+	src, err := renderEntityCode(pkg, "deskapp/src/apps/cadastro/model", types)
+	if err != nil {
+		t.Fatalf("renderEntityCode: %v", err)
+	}
+	checkGolden(t, "usuario_gen.golden", src)
+
+	// No próprio pacote entities não há import (seria um ciclo) nem qualificador
+	src, err = renderEntityCode("entities", entitiesImportPath, types)
+	if err != nil {
+		t.Fatalf("renderEntityCode: %v", err)
+	}
+	checkGolden(t, "entities_gen.golden", src)
+}
+
+func TestGenEntity_SelectedType(t *testing.T) {
+	file := filepath.Join("testdata", "gen_entity", "usuario.go")
+	if _, types, err := parseEntityStructs(file, []string{"Grupo"}); err != nil || len(types) != 1 || types[0].Name != "Grupo" {
+		t.Fatalf("Esperava só Grupo, obteve %+v (%v)", types, err)
+	}
+	if _, _, err := parseEntityStructs(file, []string{"Filtro"}); err == nil || !strings.Contains(err.Error(), "tag db") {
+		t.Errorf("Filtro sem tags db deveria falhar, obteve %v", err)
+	}
+	if _, _, err := parseEntityStructs(file, []string{"Pedido"}); err == nil {
+		t.Error("Struct inexistente deveria falhar")
+	}
+}
+
+func TestGenEntity_DuplicateColumn(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "conta.go")
+	for _, fields := range []string{
+		"Nome, Login string `db:\"nome\"`",
+		"Nome string `db:\"nome\"`\n\tLogin string `db:\"nome,omitempty\"`",
+	} {
+		src := "package cadastro\n\ntype Conta struct {\n\t" + fields + "\n}\n"
+		if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := parseEntityStructs(file, nil); err == nil || !strings.Contains(err.Error(), "repetida") {
+			t.Errorf("Coluna repetida deveria falhar (%s), obteve %v", fields, err)
+		}
+	}
+}
+
+func TestPackageImportPath(t *testing.T) {
+	got, err := packageImportPath(filepath.Join("..", "..", "apps", "core", "model", "entities"))
+	if err != nil || got != entitiesImportPath {
+		t.Errorf("Esperava %s, obteve %q (%v)", entitiesImportPath, got, err)
+	}
+	if got, _ := packageImportPath("."); got != "deskapp/src/internal/scripts" {
+		t.Errorf("Esperava deskapp/src/internal/scripts, obteve %q", got)
+	}
+}
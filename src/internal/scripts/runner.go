@@ -17,7 +17,8 @@ func registerScripts() {
     Register(&CreateAppScript{})
     Register(&TableMapScript{})
     Register(&CreateDTOScript{})
-    Register(&MigrationManager{})
+    Register(&MigrateScript{})
+    Register(&GenEntityScript{})
 }
 var logger *utils.Logger
 
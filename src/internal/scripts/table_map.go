@@ -41,6 +41,28 @@ type StructField struct {
 	JSONName string
 }
 
+// ForeignKey descreve uma FK de coluna única entre duas tabelas
+type ForeignKey struct {
+	Schema    string // tabela que possui a coluna
+	Table     string
+	Column    string
+	RefSchema string // tabela referenciada
+	RefTable  string
+	RefColumn string
+}
+
+// RelationField é uma relação gerada na entidade (campo + entrada em Relations())
+type RelationField struct {
+	Name       string // ex: Pedidos (nome do campo)
+	Kind       string // BelongsTo ou HasMany
+	GoType     string // ex: *Usuario ou []*Pedido
+	JSONName   string // ex: pedidos
+	Table      string
+	Schema     string
+	LocalKey   string
+	ForeignKey string
+}
+
 // StructConfig foi atualizada para incluir os novos campos
 type StructConfig struct {
 	AppName               string // ex: dash
@@ -51,6 +73,8 @@ type StructConfig struct {
 	EntitiesPackagePath   string // ex: deskapp/src/apps/dash/model/entities
 	RepositoryPackageName string // ex: usuario (minúsculo)
 	Fields                []StructField
+	PrimaryKeys           []string // ex: [id] ou [pedido_id linha]
+	Relations             []RelationField
 }
 
 // MapTableToStruct é a função principal que executa o script
@@ -63,17 +87,25 @@ func MapTableToStruct() error {
 		return fmt.Errorf("falha ao abrir conexão com DB: %v", err)
 	}
 	reader := bufio.NewReader(os.Stdin)
+	inspector := postgresInspector
+	if database.IsSQLite(cfg.DBDSN) {
+		inspector = sqliteInspector
+	}
 
 	// 1. Coletar informações do usuário
 	fmt.Print("📦 Nome do App: ")
 	appName, _ := reader.ReadString('\n')
 	appName = strings.TrimSpace(appName)
 
-	fmt.Print("📜 Schema (public): ")
-	schemaName, _ := reader.ReadString('\n')
-	schemaName = strings.TrimSpace(schemaName)
-	if schemaName == "" {
-		schemaName = "public"
+	// O SQLite não tem schemas; "main" é o nome do próprio arquivo
+	schemaName := "main"
+	if !database.IsSQLite(cfg.DBDSN) {
+		fmt.Print("📜 Schema (public): ")
+		schemaName, _ = reader.ReadString('\n')
+		schemaName = strings.TrimSpace(schemaName)
+		if schemaName == "" {
+			schemaName = "public"
+		}
 	}
 
 	fmt.Print("🧾 Nome da Tabela: ")
@@ -83,7 +115,7 @@ func MapTableToStruct() error {
 	defer db.Close()
 
 	// 3. Inspecionar a Tabela
-	columns, err := inspectTable(db, schemaName, tableName)
+	columns, err := inspector.columns(db, schemaName, tableName)
 	if err != nil {
 		return fmt.Errorf("falha ao inspecionar tabela: %v", err)
 	}
@@ -92,11 +124,23 @@ func MapTableToStruct() error {
 		return fmt.Errorf("tabela '%s.%s' não encontrada ou está vazia", schemaName, tableName)
 	}
 
+	primaryKeys, err := inspector.primaryKeys(db, schemaName, tableName)
+	if err != nil {
+		return fmt.Errorf("falha ao inspecionar chave primária: %v", err)
+	}
+	if len(primaryKeys) == 0 {
+		logger.Warningf("Tabela '%s.%s' não possui chave primária; Update/Delete não estarão disponíveis", schemaName, tableName)
+	}
+
+	foreignKeys, err := inspector.foreignKeys(db, schemaName, tableName)
+	if err != nil {
+		return fmt.Errorf("falha ao inspecionar chaves estrangeiras: %v", err)
+	}
+
 	fmt.Printf("🔍 Encontradas %d colunas. Gerando arquivos...\n", len(columns))
 
 	// 4. Montar configuração do Struct
-	modelName := snakeToCamel(tableName)
-	capitalizedModelName := titler.String(modelName)
+	capitalizedModelName := modelNameFor(tableName)
 	entitiesPackagePath := filepath.Join("deskapp/src/apps", appName, "model", "entities")
 
 	config := StructConfig{
@@ -109,6 +153,7 @@ func MapTableToStruct() error {
 		EntitiesPackagePath:   strings.ReplaceAll(entitiesPackagePath, "\\", "/"),
 		RepositoryPackageName: strings.ToLower(tableName), // ex: usuario
 		Fields:                make([]StructField, 0),
+		PrimaryKeys:           primaryKeys,
 	}
 
 	imports := make(map[string]bool)
@@ -132,6 +177,8 @@ func MapTableToStruct() error {
 		})
 	}
 
+	config.Relations = buildRelations(appName, schemaName, tableName, config.Fields, foreignKeys)
+
 	// === 5. GERAR ARQUIVO DE ENTIDADE (MODELO) ===
 	modelFileName := fmt.Sprintf("%s.go", tableName)
 	modelTargetPath := filepath.Join("src", "apps", appName, "model", "entities", modelFileName)
@@ -177,6 +224,150 @@ func inspectTable(db *sql.DB, schemaName, tableName string) ([]ColumnInfo, error
 	return columns, nil
 }
 
+// inspectPrimaryKeys lê as colunas da constraint PRIMARY KEY na ordem da chave
+func inspectPrimaryKeys(db *sql.DB, schemaName, tableName string) ([]string, error) {
+	query := `
+	SELECT kcu.column_name
+	FROM information_schema.table_constraints tc
+	JOIN information_schema.key_column_usage kcu
+	  ON kcu.constraint_name = tc.constraint_name
+	 AND kcu.constraint_schema = tc.constraint_schema
+	 AND kcu.table_name = tc.table_name
+	WHERE tc.constraint_type = 'PRIMARY KEY'
+	  AND tc.table_schema = $1
+	  AND tc.table_name = $2
+	ORDER BY kcu.ordinal_position;
+	`
+	rows, err := db.Query(query, schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+		keys = append(keys, col)
+	}
+	return keys, rows.Err()
+}
+
+// inspectForeignKeys lê as FKs de coluna única que saem da tabela (ela referencia
+// outra) e as que chegam nela (outra tabela a referencia). FKs compostas são ignoradas.
+func inspectForeignKeys(db *sql.DB, schemaName, tableName string) ([]ForeignKey, error) {
+	const base = `
+	SELECT tc.table_schema, tc.table_name, kcu.column_name,
+	       ccu.table_schema, ccu.table_name, ccu.column_name
+	FROM information_schema.table_constraints tc
+	JOIN information_schema.key_column_usage kcu
+	  ON kcu.constraint_name = tc.constraint_name
+	 AND kcu.constraint_schema = tc.constraint_schema
+	JOIN information_schema.constraint_column_usage ccu
+	  ON ccu.constraint_name = tc.constraint_name
+	 AND ccu.constraint_schema = tc.constraint_schema
+	WHERE tc.constraint_type = 'FOREIGN KEY'
+	  AND NOT EXISTS (
+	      SELECT 1 FROM information_schema.key_column_usage k2
+	      WHERE k2.constraint_name = tc.constraint_name
+	        AND k2.constraint_schema = tc.constraint_schema
+	        AND k2.ordinal_position > 1
+	  )
+	  AND %s
+	ORDER BY tc.table_name, kcu.column_name;
+	`
+	outgoing := fmt.Sprintf(base, "tc.table_schema = $1 AND tc.table_name = $2")
+	incoming := fmt.Sprintf(base, "ccu.table_schema = $1 AND ccu.table_name = $2")
+
+	var keys []ForeignKey
+	for _, query := range []string{outgoing, incoming} {
+		rows, err := db.Query(query, schemaName, tableName)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var fk ForeignKey
+			if err := rows.Scan(&fk.Schema, &fk.Table, &fk.Column, &fk.RefSchema, &fk.RefTable, &fk.RefColumn); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			keys = append(keys, fk)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// buildRelations converte as FKs em relações da entidade. Só gera a relação
+// quando a entidade do outro lado já existe no app, senão o código não compilaria.
+func buildRelations(appName, schemaName, tableName string, fields []StructField, foreignKeys []ForeignKey) []RelationField {
+	used := make(map[string]bool)
+	for _, f := range fields {
+		used[f.GoName] = true
+	}
+
+	var relations []RelationField
+	for _, fk := range foreignKeys {
+		var rel RelationField
+		var other string
+		if fk.Schema == schemaName && fk.Table == tableName {
+			// Esta tabela referencia outra: pedidos.usuario_id -> usuarios.id
+			other = fk.RefTable
+			jsonName := strings.TrimSuffix(fk.Column, "_id")
+			if jsonName == fk.Column {
+				jsonName = fk.RefTable
+			}
+			rel = RelationField{
+				Name:       snakeToCamel(jsonName),
+				Kind:       "BelongsTo",
+				GoType:     "*" + modelNameFor(fk.RefTable),
+				JSONName:   jsonName,
+				Table:      fk.RefTable,
+				Schema:     fk.RefSchema,
+				LocalKey:   fk.Column,
+				ForeignKey: fk.RefColumn,
+			}
+		} else {
+			// Outra tabela referencia esta: usuarios.id <- pedidos.usuario_id
+			other = fk.Table
+			rel = RelationField{
+				Name:       snakeToCamel(fk.Table),
+				Kind:       "HasMany",
+				GoType:     "[]*" + modelNameFor(fk.Table),
+				JSONName:   fk.Table,
+				Table:      fk.Table,
+				Schema:     fk.Schema,
+				LocalKey:   fk.RefColumn,
+				ForeignKey: fk.Column,
+			}
+		}
+
+		entityPath := filepath.Join("src", "apps", appName, "model", "entities", other+".go")
+		if other != tableName {
+			if _, err := os.Stat(entityPath); err != nil {
+				fmt.Printf("ℹ️  Relação %s (%s) ignorada: entidade de '%s' ainda não foi gerada neste app\n", rel.Name, rel.Kind, other)
+				continue
+			}
+		}
+		if used[rel.Name] {
+			fmt.Printf("ℹ️  Relação %s (%s) ignorada: já existe um campo com esse nome\n", rel.Name, rel.Kind)
+			continue
+		}
+		used[rel.Name] = true
+		relations = append(relations, rel)
+	}
+	return relations
+}
+
+// modelNameFor gera o nome da struct de uma tabela (ex: usuarios -> Usuarios)
+func modelNameFor(tableName string) string {
+	return titler.String(snakeToCamel(tableName))
+}
+
 func mapPostgresTypeToGoType(pgType string, isNullable string) string {
 	isNullableBool := strings.ToUpper(isNullable) == "YES"
 	switch strings.ToLower(pgType) {
@@ -269,7 +460,14 @@ func generateModelFile(targetPath string, config StructConfig, imports map[strin
 // {{.ModelName}} representa a tabela {{.TableName}} do banco de dados
 type {{.ModelName}} struct {
 {{- range .Fields}}
-	{{.GoName}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}"` + "`" + `
+	{{.GoName}} {{.GoType}} ` + "`" + `db:"{{.JSONName}}" json:"{{.JSONName}}"` + "`" + `
+{{- end}}
+{{- if .Relations}}
+
+	// Relações carregadas sob demanda com Preload
+{{- range .Relations}}
+	{{.Name}} {{.GoType}} ` + "`" + `db:"-" json:"{{.JSONName}},omitempty"` + "`" + `
+{{- end}}
 {{- end}}
 }
 
@@ -290,6 +488,37 @@ func (m *{{.ModelName}}) ScanRow(row DBScanner) error {
 {{- end}}
 	)
 }
+
+// Values retorna os valores das colunas na ordem de Columns().
+func (m *{{.ModelName}}) Values() []any {
+	return []any{
+{{- range .Fields}}
+		m.{{.GoName}},
+{{- end}}
+	}
+}
+{{- if .PrimaryKeys}}
+
+// PrimaryKey retorna as colunas da chave primária de {{.TableName}}.
+func (m *{{.ModelName}}) PrimaryKey() []string {
+	return []string{
+{{- range .PrimaryKeys}}
+		"{{.}}",
+{{- end}}
+	}
+}
+{{- end}}
+{{- if .Relations}}
+
+// Relations declara as relações de {{.TableName}} usadas pelo Preload.
+func (m *{{.ModelName}}) Relations() []entities.Relation {
+	return []entities.Relation{
+{{- range .Relations}}
+		{Name: "{{.Name}}", Kind: entities.{{.Kind}}, Table: "{{.Table}}", Schema: "{{.Schema}}", LocalKey: "{{.LocalKey}}", ForeignKey: "{{.ForeignKey}}"},
+{{- end}}
+	}
+}
+{{- end}}
 `
 	// Adiciona imports dinâmicos
 	actualImports := map[string]bool{
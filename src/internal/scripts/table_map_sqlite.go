@@ -0,0 +1,150 @@
+package main
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// tableInspector reúne as consultas de introspecção de um banco.
+type tableInspector struct {
+	columns     func(db *sql.DB, schemaName, tableName string) ([]ColumnInfo, error)
+	primaryKeys func(db *sql.DB, schemaName, tableName string) ([]string, error)
+	foreignKeys func(db *sql.DB, schemaName, tableName string) ([]ForeignKey, error)
+}
+
+// postgresInspector lê o information_schema do Postgres.
+var postgresInspector = tableInspector{
+	columns:     inspectTable,
+	primaryKeys: inspectPrimaryKeys,
+	foreignKeys: inspectForeignKeys,
+}
+
+// sqliteInspector lê os PRAGMAs do SQLite. O schema é ignorado: o SQLite
+// não tem namespaces, o arquivo inteiro é o banco "main".
+var sqliteInspector = tableInspector{
+	columns:     inspectSQLiteTable,
+	primaryKeys: inspectSQLitePrimaryKeys,
+	foreignKeys: inspectSQLiteForeignKeys,
+}
+
+// sqliteTypeLength remove o tamanho/precisão do tipo declarado: VARCHAR(255) -> varchar
+var sqliteTypeLength = regexp.MustCompile(`\s*\(.*\)$`)
+
+// normalizeSQLiteType traduz o tipo declarado no SQLite para o nome
+// equivalente do Postgres entendido por mapPostgresTypeToGoType.
+// INTEGER no SQLite tem 64 bits, por isso vira bigint.
+func normalizeSQLiteType(declared string) string {
+	t := strings.ToLower(sqliteTypeLength.ReplaceAllString(strings.TrimSpace(declared), ""))
+	switch t {
+	case "integer", "int":
+		return "bigint"
+	case "datetime":
+		return "timestamp"
+	case "double", "float":
+		return "double precision"
+	}
+	return t
+}
+
+// inspectSQLiteTable lê as colunas com PRAGMA table_info, na ordem da tabela.
+func inspectSQLiteTable(db *sql.DB, _, tableName string) ([]ColumnInfo, error) {
+	rows, err := db.Query(`SELECT name, type, "notnull", pk FROM pragma_table_info(?1) ORDER BY cid`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		var notNull, pk int
+		if err := rows.Scan(&col.ColumnName, &col.DataType, &notNull, &pk); err != nil {
+			return nil, err
+		}
+		col.DataType = normalizeSQLiteType(col.DataType)
+		col.IsNullable = "YES"
+		if notNull == 1 || pk > 0 {
+			col.IsNullable = "NO"
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// inspectSQLitePrimaryKeys lê as colunas da PK na ordem da chave (pk = 1, 2...).
+func inspectSQLitePrimaryKeys(db *sql.DB, _, tableName string) ([]string, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?1) WHERE pk > 0 ORDER BY pk`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			return nil, err
+		}
+		keys = append(keys, col)
+	}
+	return keys, rows.Err()
+}
+
+// inspectSQLiteForeignKeys percorre o PRAGMA foreign_key_list de todas as
+// tabelas para achar as FKs que saem da tabela e as que chegam nela. Assim
+// como no Postgres, FKs compostas são ignoradas.
+func inspectSQLiteForeignKeys(db *sql.DB, schemaName, tableName string) ([]ForeignKey, error) {
+	rows, err := db.Query(`
+	SELECT m.name, f.id, f."from", f."table", COALESCE(f."to", '')
+	FROM sqlite_master m, pragma_foreign_key_list(m.name) f
+	WHERE m.type = 'table'
+	ORDER BY m.name, f."from";
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	type constraintKey struct {
+		table string
+		id    int
+	}
+	var all []ForeignKey
+	ids := make([]constraintKey, 0)
+	count := make(map[constraintKey]int)
+	for rows.Next() {
+		var fk ForeignKey
+		var id int
+		if err := rows.Scan(&fk.Table, &id, &fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		fk.Schema, fk.RefSchema = schemaName, schemaName
+		key := constraintKey{fk.Table, id}
+		count[key]++
+		all = append(all, fk)
+		ids = append(ids, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys []ForeignKey
+	for i, fk := range all {
+		if count[ids[i]] > 1 || (fk.Table != tableName && fk.RefTable != tableName) {
+			continue
+		}
+		// "REFERENCES usuarios" sem coluna aponta para a PK da tabela referenciada
+		if fk.RefColumn == "" {
+			pks, err := inspectSQLitePrimaryKeys(db, schemaName, fk.RefTable)
+			if err != nil {
+				return nil, err
+			}
+			if len(pks) != 1 {
+				continue
+			}
+			fk.RefColumn = pks[0]
+		}
+		keys = append(keys, fk)
+	}
+	return keys, nil
+}
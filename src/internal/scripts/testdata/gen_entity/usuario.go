@@ -0,0 +1,26 @@
+package cadastro
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Usuario struct {
+	ID       int64          `db:"id" json:"id"`
+	Nome     string         `db:"nome"`
+	Email    *string        `db:"email,omitempty" json:"email"`
+	Apelido  sql.NullString `db:"apelido"`
+	CriadoEm time.Time      `db:"criado_em"`
+	Senha    string         `db:"-" json:"-"`
+	Cache    []byte
+}
+
+// Sem tags db: ignorada
+type Filtro struct {
+	Nome string
+}
+
+type Grupo struct {
+	ID   int64          `db:"id"`
+	Dono *sql.NullInt64 `db:"dono_id"`
+}
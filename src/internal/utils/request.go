@@ -3,6 +3,7 @@ package utils
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -93,3 +94,51 @@ func GetCSVFloat(value string) ([]float64, error) {
 	return values, nil
 }
 
+
+// MaxPerPage limita o per_page aceito vindo da requisição.
+const MaxPerPage = 100
+
+// SortField é um campo de ordenação lido do parâmetro "sort".
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Pagination reúne os parâmetros de listagem lidos da query string.
+type Pagination struct {
+	Page    uint64
+	PerPage uint64
+	Sort    []SortField
+	Cursor  string
+	Keyset  bool // true quando o parâmetro "cursor" foi enviado (mesmo vazio)
+}
+
+var sortFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ReadPagination lê page, per_page, sort e cursor da query string.
+// Valores inválidos caem nos padrões (page=1, per_page=20, limitado a MaxPerPage)
+// e campos de sort que não sejam identificadores simples são descartados.
+// Ex: ?page=2&per_page=50&sort=name,-created_at
+func ReadPagination(ctx *gin.Context) Pagination {
+	p := Pagination{Page: 1, PerPage: 20}
+
+	if page, err := strconv.ParseUint(ctx.Query("page"), 10, 64); err == nil && page > 0 {
+		p.Page = page
+	}
+	if perPage, err := strconv.ParseUint(ctx.Query("per_page"), 10, 64); err == nil && perPage > 0 {
+		p.PerPage = min(perPage, MaxPerPage)
+	}
+
+	for _, field := range strings.Split(ctx.Query("sort"), ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		if !sortFieldPattern.MatchString(field) {
+			continue
+		}
+		p.Sort = append(p.Sort, SortField{Column: field, Desc: desc})
+	}
+
+	p.Cursor, p.Keyset = ctx.GetQuery("cursor")
+	return p
+}
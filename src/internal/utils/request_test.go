@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newQueryContext(rawQuery string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/?"+rawQuery, nil)
+	return ctx
+}
+
+func TestReadPagination(t *testing.T) {
+	p := ReadPagination(newQueryContext("page=3&per_page=50&sort=name,-created_at"))
+
+	if p.Page != 3 || p.PerPage != 50 {
+		t.Errorf("Página inesperada: %+v", p)
+	}
+	if len(p.Sort) != 2 || p.Sort[0] != (SortField{Column: "name"}) || p.Sort[1] != (SortField{Column: "created_at", Desc: true}) {
+		t.Errorf("Sort inesperado: %+v", p.Sort)
+	}
+	if p.Keyset {
+		t.Error("Keyset não deveria estar ativo sem o parâmetro cursor")
+	}
+}
+
+func TestReadPagination_InvalidValues(t *testing.T) {
+	p := ReadPagination(newQueryContext("page=-1&per_page=100000&sort=name%3BDROP%20TABLE%20users,-&cursor="))
+
+	if p.Page != 1 {
+		t.Errorf("Esperava page=1, obteve %d", p.Page)
+	}
+	if p.PerPage != MaxPerPage {
+		t.Errorf("Esperava per_page=%d, obteve %d", MaxPerPage, p.PerPage)
+	}
+	if len(p.Sort) != 0 {
+		t.Errorf("Campos de sort inválidos deveriam ser descartados: %+v", p.Sort)
+	}
+	if !p.Keyset || p.Cursor != "" {
+		t.Errorf("Esperava keyset ativo com cursor vazio: %+v", p)
+	}
+}
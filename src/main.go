@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"deskapp/src/app"
 	"deskapp/src/apps/core"
 	"deskapp/src/internal/config"
 	"deskapp/src/internal/database"
+	"deskapp/src/internal/migrations"
 	"deskapp/src/internal/utils"
 
 	"github.com/joho/godotenv"
@@ -22,16 +24,31 @@ func init() {
 
 func main() {
 	cfg := config.NewConfig()
-	dbConn, err := database.InitDB(cfg.DBDSN)
+	database.SetPool(cfg.DBPool)
+	_, err := database.InitDB(cfg.DBDSN)
 	if err != nil {
-		logger.Warningf("Falha ao conectar com banco: %v", err)
+		logger.Warningf("Falha ao conectar com banco: %v (tentando novamente em segundo plano)", err)
 	}
-	defer dbConn.Close()
+	// Verifica a saúde do banco e reconecta com backoff se ele cair
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go database.Watch(ctx, cfg.DBRetry)
+	// Réplica e demais conexões nomeadas (DATABASE_URL_<NOME>)
+	if err := database.OpenAll(cfg.Databases); err != nil {
+		logger.Warningf("Falha ao conectar com bancos adicionais: %v", err)
+	}
+	defer database.CloseAll()
 
 
 	
 	app := app.NewAppManager(logger, cfg, StaticFS, TemplateFS)
 	app.RegisterApp(core.NewCoreApp(logger, cfg))
+	// Migrações embutidas, aplicadas em RegisterAllRoutes se DB_AUTO_MIGRATE=true
+	source, err := migrations.Embedded(MigrationsFS, "migrations")
+	if err != nil {
+		logger.Fatalf("Falha ao ler migrações embutidas: %v", err)
+	}
+	app.SetMigrations(source)
 	
 
 	app.RegisterAllRoutes()
@@ -9,3 +9,9 @@ var StaticFS embed.FS
 
 //go:embed templates/*
 var TemplateFS embed.FS
+
+// MigrationsFS leva as migrações no binário, para aplicá-las na
+// inicialização (DB_AUTO_MIGRATE=true)
+//
+//go:embed migrations/*.sql
+var MigrationsFS embed.FS